@@ -0,0 +1,59 @@
+#!/usr/bin/env bash
+# Generates the CRDs of the chart from config/crd/bases. The Certificate CRD is
+# a template of the chart since its conversion webhook is served by the release.
+set -euo pipefail
+cd "$(dirname "$0")/.."
+
+bases=config/crd/bases
+chart=charts/acm-manager
+certificates=$bases/acm-manager.io_certificates.yaml
+
+out=$chart/crds/crds.yaml
+: > "$out"
+first=1
+for f in $(ls $bases/*.yaml | sort); do
+  if [ "$f" = "$certificates" ]; then
+    continue
+  fi
+  if [ $first = 0 ]; then
+    echo '---' >> "$out"
+  fi
+  first=0
+  sed '1{/^---$/d}' "$f" >> "$out"
+done
+
+awk '
+  NR == 1 && /^---$/ { next }
+  /^  annotations:$/ && !annotations {
+    print
+    print "    helm.sh/resource-policy: keep"
+    print "    {{- if .Values.conversionWebhook.enabled }}"
+    print "    cert-manager.io/inject-ca-from: {{ .Release.Namespace }}/{{ include \"acm-manager.fullname\" . }}-webhook"
+    print "    {{- end }}"
+    annotations = 1
+    next
+  }
+  /^  name: / && !labels {
+    print "  labels:"
+    print "    {{- include \"acm-manager.labels\" . | nindent 4 }}"
+    labels = 1
+  }
+  /^spec:$/ && !conversion {
+    print
+    print "  {{- if .Values.conversionWebhook.enabled }}"
+    print "  conversion:"
+    print "    strategy: Webhook"
+    print "    webhook:"
+    print "      clientConfig:"
+    print "        service:"
+    print "          name: {{ include \"acm-manager.fullname\" . }}-webhook"
+    print "          namespace: {{ .Release.Namespace }}"
+    print "          path: /convert"
+    print "      conversionReviewVersions:"
+    print "        - v1"
+    print "  {{- end }}"
+    conversion = 1
+    next
+  }
+  { print }
+' "$certificates" > "$chart/templates/crd-certificates.yaml"
@@ -17,28 +17,43 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	pkgscheme "sigs.k8s.io/controller-runtime/pkg/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 	dnsendpoint "sigs.k8s.io/external-dns/apis/v1alpha1"
 
 	certificatev1alpha1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1alpha1"
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
 	"vdesjardins/acm-manager/pkg/controllers"
+	"vdesjardins/acm-manager/pkg/controllers/external_api_clients"
+	"vdesjardins/acm-manager/pkg/policy"
+	"vdesjardins/acm-manager/pkg/webhooks"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -56,6 +71,8 @@ func init() {
 	utilruntime.Must(schemeBuilder.AddToScheme(scheme))
 
 	utilruntime.Must(certificatev1alpha1.AddToScheme(scheme))
+	utilruntime.Must(certificatev1beta1.AddToScheme(scheme))
+	utilruntime.Must(cmapi.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -65,12 +82,62 @@ func main() {
 	var probeAddr string
 	var managerOwnerName string
 	var ingressAutoDetect bool
+	var ingressClassControllers string
+	var ingressHostSource string
+	var ingressMaxCertificateHosts int
+	var ingressGroupNamespace string
+	var ingressWildcardDomains string
+	var ingressCertificateNameTemplate string
 	var acmCleanupJobInternval time.Duration
+	var acmCleanupGracePeriod time.Duration
+	var acmCleanupDryRun bool
+	var requestBudgetLimit int
+	var requestBudgetThreshold float64
+	var requestBudgetWindow time.Duration
+	var requestBudgetNamespace string
+	var requestBudgetName string
+	var specSettleWindow time.Duration
+	var verifyInterval time.Duration
+	var enableWebhooks bool
+	var enableConversionWebhook bool
+	var enableCertManagerIssuer bool
+	var csrSignerIssuer string
+	var allowedIssuerRoleARNs string
+	var enableTrustBundles bool
+	var maxSubjectAlternativeNames int
+	var defaultKeyAlgorithm string
+	var defaultDeletionPolicy string
+	var defaultTags string
 	flag.StringVar(&managerOwnerName, "acm-owner-id", "acm-manager", "ACM manager name used to tag AWS ACM certificates")
 	flag.DurationVar(&acmCleanupJobInternval, "acm-cleanup-interval", time.Hour*6, "ACM cleanup job interval")
+	flag.DurationVar(&acmCleanupGracePeriod, "acm-cleanup-grace-period", time.Hour*24, "Time an orphaned ACM certificate is kept before being deleted by the cleanup job")
+	flag.BoolVar(&acmCleanupDryRun, "acm-cleanup-dry-run", false, "Only report orphaned ACM certificates without tagging or deleting them")
+	flag.IntVar(&requestBudgetLimit, "acm-request-budget-limit", 0, "Number of ACM certificates that can be requested per account and region during the budget window. 0 disables the budget")
+	flag.Float64Var(&requestBudgetThreshold, "acm-request-budget-threshold", 0.9, "Fraction of the request budget limit after which new ACM certificate requests are refused")
+	flag.DurationVar(&requestBudgetWindow, "acm-request-budget-window", time.Hour*24*365, "Rolling window used to count ACM certificate requests")
+	flag.StringVar(&requestBudgetNamespace, "acm-request-budget-namespace", os.Getenv("POD_NAMESPACE"), "Namespace of the ConfigMap holding the request budget")
+	flag.StringVar(&requestBudgetName, "acm-request-budget-configmap", "acm-manager-request-budget", "Name of the ConfigMap holding the request budget")
+	flag.DurationVar(&specSettleWindow, "spec-settle-window", 0, "Time a Certificate spec change must stay unchanged before a new ACM certificate is requested. 0 disables the wait")
+	flag.DurationVar(&verifyInterval, "acm-verify-interval", time.Hour, "Interval at which ACM certificates are compared with an unchanged Certificate spec. 0 compares on every reconcile")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false, "Enable the Certificate admission webhooks. A serving certificate must be available to the webhook server")
+	flag.BoolVar(&enableConversionWebhook, "enable-conversion-webhook", false, "Serve the conversion webhook of the Certificate CRD on /convert, independently of --enable-webhooks. A serving certificate must be available to the webhook server")
+	flag.BoolVar(&enableCertManagerIssuer, "enable-cert-manager-issuer", false, "Sign the cert-manager CertificateRequests referencing an ACMIssuer or ClusterACMIssuer. The cert-manager CRDs must be installed")
+	flag.StringVar(&csrSignerIssuer, "csr-signer-issuer", "", "ClusterACMIssuer whose private CA signs the CertificateSigningRequests of the "+controllers.PrivateCASignerName+" signer. Empty disables the signer")
+	flag.StringVar(&allowedIssuerRoleARNs, "allowed-issuer-role-arns", "", "Comma separated IAM roles the namespaced ACMIssuers can assume, an entry ending with * allows the roles starting with it. ClusterACMIssuers can assume any role")
+	flag.BoolVar(&enableTrustBundles, "enable-trust-bundles", false, "Publish the certificates of the Private CAs of the TrustBundles to ConfigMaps")
+	flag.IntVar(&maxSubjectAlternativeNames, "max-subject-alternative-names", webhooks.DefaultMaxSubjectAlternativeNames, "Number of domain names allowed in a certificate by the ACM quota of the account")
+	flag.StringVar(&defaultKeyAlgorithm, "default-key-algorithm", string(certificatev1beta1.CertificateKeyAlgorithmRSA2048), "Key algorithm set by the defaulting webhook on Certificates that do not specify one")
+	flag.StringVar(&defaultDeletionPolicy, "default-deletion-policy", string(certificatev1beta1.CertificateDeletionPolicyDelete), "Deletion policy (Delete or Retain) set by the defaulting webhook on Certificates that do not specify one")
+	flag.StringVar(&defaultTags, "default-tags", "", "Comma separated key=value tags added by the defaulting webhook to every Certificate")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&ingressAutoDetect, "ingress-auto-detect", true, "automatically create certificate request if type is ALB and internet-facing")
+	flag.StringVar(&ingressHostSource, "ingress-host-source", controllers.HostSourceTLS, "Hosts of the Certificates generated for Ingresses: tls, rules or all. Overridden by the "+controllers.IngressHostSourceKey+" annotation")
+	flag.IntVar(&ingressMaxCertificateHosts, "ingress-max-certificate-hosts", controllers.IngressMaxCertificateHosts, "Maximum number of hosts of a Certificate generated for an Ingress, the ACM quota of names per certificate. The hosts are split across several Certificates beyond it")
+	flag.StringVar(&ingressGroupNamespace, "ingress-group-namespace", "", "Namespace of the Certificates shared by the Ingresses of an ALB IngressGroup. Empty generates a Certificate per Ingress")
+	flag.StringVar(&ingressWildcardDomains, "ingress-wildcard-domains", "", "Comma separated parent domains whose Ingress hosts are collapsed into a wildcard name. Overridden by the "+controllers.IngressWildcardDomainsKey+" annotation")
+	flag.StringVar(&ingressCertificateNameTemplate, "ingress-certificate-name-template", "{{ .Name }}", "Go template of the name of the Certificates generated for Ingresses, executed with the Ingress metadata")
+	flag.StringVar(&ingressClassControllers, "ingress-class-controllers", strings.Join(controllers.IngressClassControllers, ","), "Comma separated controllers of the IngressClasses whose internet-facing Ingresses are auto detected")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", true,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -84,7 +151,26 @@ func main() {
 
 	controllers.ACMManagerOwnerName = managerOwnerName
 	controllers.IngressAutoDetect = ingressAutoDetect
-	controllers.ACMCertificateCleanupInterval = acmCleanupJobInternval
+	controllers.IngressClassControllers = strings.Split(ingressClassControllers, ",")
+	controllers.IngressHostSource = ingressHostSource
+	if ingressMaxCertificateHosts < 1 {
+		setupLog.Error(nil, "ingress-max-certificate-hosts must be at least 1")
+		os.Exit(1)
+	}
+	controllers.IngressMaxCertificateHosts = ingressMaxCertificateHosts
+	controllers.IngressGroupNamespace = ingressGroupNamespace
+	nameTemplate, err := controllers.ParseIngressCertificateNameTemplate(ingressCertificateNameTemplate)
+	if err != nil {
+		setupLog.Error(err, "invalid ingress-certificate-name-template")
+		os.Exit(1)
+	}
+	controllers.IngressCertificateNameTemplate = nameTemplate
+	if ingressWildcardDomains != "" {
+		controllers.IngressWildcardDomains = strings.Split(ingressWildcardDomains, ",")
+	}
+	if allowedIssuerRoleARNs != "" {
+		controllers.AllowedIssuerRoleARNs = strings.Split(allowedIssuerRoleARNs, ",")
+	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
@@ -97,28 +183,168 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "99c2bcfe.acm-manager.io",
+		// the request budget reads its ConfigMap without the cache
+		Cache: cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&core.ConfigMap{}: controllers.TrustBundleConfigMapCache(),
+			},
+		},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	awsConfig, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		setupLog.Error(err, "unable to load AWS config")
+		os.Exit(1)
+	}
+	acmClient := external_api_clients.NewAcmClient(acm.NewFromConfig(awsConfig))
+	awsClients := external_api_clients.NewClientFactory(awsConfig)
+	domainPolicy := &policy.Checker{Reader: mgr.GetClient()}
+
+	var requestBudget *controllers.RequestBudget
+	if requestBudgetLimit > 0 {
+		if requestBudgetNamespace == "" {
+			setupLog.Error(nil, "request budget namespace must be set with --acm-request-budget-namespace or POD_NAMESPACE")
+			os.Exit(1)
+		}
+		requestBudget = &controllers.RequestBudget{
+			Client:    mgr.GetClient(),
+			Reader:    mgr.GetAPIReader(),
+			STSClient: external_api_clients.NewStsClient(sts.NewFromConfig(awsConfig)),
+			Region:    awsConfig.Region,
+			Namespace: requestBudgetNamespace,
+			Name:      requestBudgetName,
+			Limit:     requestBudgetLimit,
+			Threshold: requestBudgetThreshold,
+			Window:    requestBudgetWindow,
+		}
+	}
+
 	if err = (&controllers.CertificateReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		ACMClient:        acmClient,
+		Clients:          awsClients,
+		Policy:           domainPolicy,
+		RequestBudget:    requestBudget,
+		SpecSettleWindow: specSettleWindow,
+		VerifyInterval:   verifyInterval,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Certificate")
 		os.Exit(1)
 	}
+	for _, kind := range []string{certificatev1beta1.IssuerKind, certificatev1beta1.ClusterIssuerKind} {
+		if err = (&controllers.ACMIssuerReconciler{
+			Client:  mgr.GetClient(),
+			Scheme:  mgr.GetScheme(),
+			Clients: awsClients,
+			Kind:    kind,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", kind)
+			os.Exit(1)
+		}
+	}
+	if enableCertManagerIssuer {
+		if err = (&controllers.CertificateRequestReconciler{
+			Client:  mgr.GetClient(),
+			Scheme:  mgr.GetScheme(),
+			Clients: awsClients,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CertificateRequest")
+			os.Exit(1)
+		}
+	}
+	if csrSignerIssuer != "" {
+		if err = (&controllers.CertificateSigningRequestReconciler{
+			Client:     mgr.GetClient(),
+			Scheme:     mgr.GetScheme(),
+			Clients:    awsClients,
+			IssuerName: csrSignerIssuer,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CertificateSigningRequest")
+			os.Exit(1)
+		}
+	}
+	if enableTrustBundles {
+		if err = (&controllers.TrustBundleReconciler{
+			Client:  mgr.GetClient(),
+			Scheme:  mgr.GetScheme(),
+			Clients: awsClients,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "TrustBundle")
+			os.Exit(1)
+		}
+	}
 	if err = (&controllers.IngressReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Region:    awsConfig.Region,
+		APIReader: mgr.GetAPIReader(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Ingress")
 		os.Exit(1)
 	}
-	controllers.StartACMCertificateCleanupJob()
+	if ingressGroupNamespace != "" {
+		if err = (&controllers.IngressGroupReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+			Policy: domainPolicy,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "IngressGroup")
+			os.Exit(1)
+		}
+	}
+	if err = (&controllers.ACMCertificateCleanupJob{
+		Client:      mgr.GetClient(),
+		ACMClient:   acmClient,
+		Clients:     awsClients,
+		Interval:    acmCleanupJobInternval,
+		GracePeriod: acmCleanupGracePeriod,
+		DryRun:      acmCleanupDryRun,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create cleanup job", "job", "ACMCertificateCleanup")
+		os.Exit(1)
+	}
+
+	if enableConversionWebhook {
+		// the CRD converts the versions of stored Certificates with it, the
+		// admission webhooks are optional
+		mgr.GetWebhookServer().Register("/convert", conversion.NewWebhookHandler(mgr.GetScheme(), mgr.GetConverterRegistry()))
+	}
 
+	if enableWebhooks {
+		tags, err := parseTags(defaultTags)
+		if err != nil {
+			setupLog.Error(err, "invalid default tags")
+			os.Exit(1)
+		}
+		if err = (&webhooks.CertificateDefaulter{
+			KeyAlgorithm:   certificatev1beta1.CertificateKeyAlgorithm(defaultKeyAlgorithm),
+			DeletionPolicy: certificatev1beta1.CertificateDeletionPolicy(defaultDeletionPolicy),
+			Tags:           tags,
+			Reader:         mgr.GetClient(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Certificate")
+			os.Exit(1)
+		}
+		if err = (&webhooks.CertificateValidator{
+			MaxSubjectAlternativeNames: maxSubjectAlternativeNames,
+			Policy:                     domainPolicy,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Certificate")
+			os.Exit(1)
+		}
+		if err = (&webhooks.IngressValidator{
+			Policy: domainPolicy,
+			Reader: mgr.GetClient(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Ingress")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -136,3 +362,19 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// parseTags parses a comma separated list of key=value tags.
+func parseTags(value string) (map[string]string, error) {
+	tags := map[string]string{}
+	for _, tag := range strings.Split(value, ",") {
+		if strings.TrimSpace(tag) == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(tag, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("tag %q is not in the key=value format", tag)
+		}
+		tags[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return tags, nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+// ConversionDataAnnotation holds the v1beta1 spec of a Certificate when it
+// can not be represented in v1alpha1, so no data is lost on a round trip.
+const ConversionDataAnnotation = "acm-manager.io/conversion-data"
+
+var _ conversion.Convertible = &Certificate{}
+
+// ConvertTo converts this Certificate to the hub version (v1beta1).
+func (src *Certificate) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.Certificate)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = convertSpecToV1beta1(&src.Spec)
+	convertStatusToV1beta1(&src.Status, &dst.Status)
+
+	data, ok := dst.Annotations[ConversionDataAnnotation]
+	if !ok {
+		return nil
+	}
+	delete(dst.Annotations, ConversionDataAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	restored := v1beta1.CertificateSpec{}
+	if err := json.Unmarshal([]byte(data), &restored); err != nil {
+		return fmt.Errorf("unable to decode %s annotation: %w", ConversionDataAnnotation, err)
+	}
+
+	// fields that exist in v1alpha1 win over the saved ones since they may have
+	// been changed through v1alpha1
+	restored.CommonName = dst.Spec.CommonName
+	restored.SubjectAlternativeNames = dst.Spec.SubjectAlternativeNames
+	restored.Tags = dst.Spec.Tags
+	restored.DeletionPolicy = dst.Spec.DeletionPolicy
+	if restored.Options != nil || src.Spec.KeyAlgorithm != "" {
+		if restored.Options == nil {
+			restored.Options = &v1beta1.CertificateOptions{}
+		}
+		restored.Options.KeyAlgorithm = v1beta1.CertificateKeyAlgorithm(src.Spec.KeyAlgorithm)
+	}
+	dst.Spec = restored
+
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1beta1) to this version.
+func (dst *Certificate) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.Certificate)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = CertificateSpec{
+		CommonName:              src.Spec.CommonName,
+		SubjectAlternativeNames: src.Spec.SubjectAlternativeNames,
+		KeyAlgorithm:            CertificateKeyAlgorithm(src.Spec.KeyAlgorithm()),
+		Tags:                    src.Spec.Tags,
+		DeletionPolicy:          CertificateDeletionPolicy(src.Spec.DeletionPolicy),
+	}
+	convertStatusFromV1beta1(&src.Status, &dst.Status)
+
+	// only save the spec when v1alpha1 can not represent all of it
+	spec := convertSpecToV1beta1(&dst.Spec)
+	if equality.Semantic.DeepEqual(spec, src.Spec) {
+		return nil
+	}
+
+	data, err := json.Marshal(src.Spec)
+	if err != nil {
+		return fmt.Errorf("unable to encode %s annotation: %w", ConversionDataAnnotation, err)
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = map[string]string{}
+	}
+	dst.Annotations[ConversionDataAnnotation] = string(data)
+
+	return nil
+}
+
+func convertSpecToV1beta1(src *CertificateSpec) v1beta1.CertificateSpec {
+	dst := v1beta1.CertificateSpec{
+		CommonName:              src.CommonName,
+		SubjectAlternativeNames: src.SubjectAlternativeNames,
+		Tags:                    src.Tags,
+		DeletionPolicy:          v1beta1.CertificateDeletionPolicy(src.DeletionPolicy),
+	}
+	if src.KeyAlgorithm != "" {
+		dst.Options = &v1beta1.CertificateOptions{
+			KeyAlgorithm: v1beta1.CertificateKeyAlgorithm(src.KeyAlgorithm),
+		}
+	}
+	return dst
+}
+
+func convertStatusToV1beta1(src *CertificateStatus, dst *v1beta1.CertificateStatus) {
+	dst.CertificateArn = src.CertificateArn
+	dst.ResourceRecords = nil
+	if src.ResourceRecords != nil {
+		dst.ResourceRecords = make([]v1beta1.ResourceRecord, 0, len(src.ResourceRecords))
+	}
+	for _, rr := range src.ResourceRecords {
+		dst.ResourceRecords = append(dst.ResourceRecords, v1beta1.ResourceRecord(rr))
+	}
+	dst.Status = v1beta1.CertificateStatusType(src.Status)
+	dst.NotBefore = src.NotBefore
+	dst.NotAfter = src.NotAfter
+	dst.SpecHash = src.SpecHash
+	dst.LastVerifiedTime = src.LastVerifiedTime
+	dst.PendingSpecHash = src.PendingSpecHash
+	dst.PendingSpecSince = src.PendingSpecSince
+	dst.RequestedIssuerRef = (*v1beta1.IssuerReference)(src.RequestedIssuerRef)
+	dst.RequestedRegion = src.RequestedRegion
+	dst.Conditions = src.Conditions
+}
+
+func convertStatusFromV1beta1(src *v1beta1.CertificateStatus, dst *CertificateStatus) {
+	dst.CertificateArn = src.CertificateArn
+	dst.ResourceRecords = nil
+	if src.ResourceRecords != nil {
+		dst.ResourceRecords = make([]ResourceRecord, 0, len(src.ResourceRecords))
+	}
+	for _, rr := range src.ResourceRecords {
+		dst.ResourceRecords = append(dst.ResourceRecords, ResourceRecord(rr))
+	}
+	dst.Status = CertificateStatusType(src.Status)
+	dst.NotBefore = src.NotBefore
+	dst.NotAfter = src.NotAfter
+	dst.SpecHash = src.SpecHash
+	dst.LastVerifiedTime = src.LastVerifiedTime
+	dst.PendingSpecHash = src.PendingSpecHash
+	dst.PendingSpecSince = src.PendingSpecSince
+	dst.RequestedIssuerRef = (*IssuerReference)(src.RequestedIssuerRef)
+	dst.RequestedRegion = src.RequestedRegion
+	dst.Conditions = src.Conditions
+}
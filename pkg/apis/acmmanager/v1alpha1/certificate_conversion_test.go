@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"math/rand"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/randfill"
+
+	"vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+const fuzzIterations = 1000
+
+func newFuzzer(t *testing.T) *randfill.Filler {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	seed := rand.Int63()
+	t.Logf("fuzzer seed: %d", seed)
+	return fuzzer.FuzzerFor(metafuzzer.Funcs, rand.NewSource(seed), serializer.NewCodecFactory(scheme))
+}
+
+func TestCertificateHubSpokeHubRoundTrip(t *testing.T) {
+	f := newFuzzer(t)
+
+	for i := 0; i < fuzzIterations; i++ {
+		hub := &v1beta1.Certificate{}
+		f.Fill(hub)
+		// the type is set by the conversion webhook, not by the conversion functions
+		hub.TypeMeta = metav1.TypeMeta{}
+
+		spoke := &Certificate{}
+		if err := spoke.ConvertFrom(hub.DeepCopy()); err != nil {
+			t.Fatalf("unable to convert from hub: %v", err)
+		}
+		got := &v1beta1.Certificate{}
+		if err := spoke.ConvertTo(got); err != nil {
+			t.Fatalf("unable to convert to hub: %v", err)
+		}
+
+		if !equality.Semantic.DeepEqual(hub, got) {
+			t.Fatalf("hub changed after round trip:\n%s", diff.Diff(hub, got))
+		}
+	}
+}
+
+func TestCertificateSpokeHubSpokeRoundTrip(t *testing.T) {
+	f := newFuzzer(t)
+
+	for i := 0; i < fuzzIterations; i++ {
+		spoke := &Certificate{}
+		f.Fill(spoke)
+		spoke.TypeMeta = metav1.TypeMeta{}
+
+		hub := &v1beta1.Certificate{}
+		if err := spoke.DeepCopy().ConvertTo(hub); err != nil {
+			t.Fatalf("unable to convert to hub: %v", err)
+		}
+		got := &Certificate{}
+		if err := got.ConvertFrom(hub); err != nil {
+			t.Fatalf("unable to convert from hub: %v", err)
+		}
+
+		if !equality.Semantic.DeepEqual(spoke, got) {
+			t.Fatalf("spoke changed after round trip:\n%s", diff.Diff(spoke, got))
+		}
+	}
+}
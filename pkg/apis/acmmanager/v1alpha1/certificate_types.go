@@ -35,6 +35,28 @@ const (
 	CertificateStatusRequested          CertificateStatusType = "Requested"
 )
 
+// CertificateKeyAlgorithm is the algorithm of the key pair of the ACM certificate
+// +kubebuilder:validation:Enum=RSA_2048;EC_prime256v1;EC_secp384r1
+type CertificateKeyAlgorithm string
+
+const (
+	CertificateKeyAlgorithmRSA2048      CertificateKeyAlgorithm = "RSA_2048"
+	CertificateKeyAlgorithmECPrime256v1 CertificateKeyAlgorithm = "EC_prime256v1"
+	CertificateKeyAlgorithmECSecp384r1  CertificateKeyAlgorithm = "EC_secp384r1"
+)
+
+// CertificateDeletionPolicy tells what happens to the ACM certificate when the
+// Certificate is deleted
+// +kubebuilder:validation:Enum=Delete;Retain
+type CertificateDeletionPolicy string
+
+const (
+	// CertificateDeletionPolicyDelete deletes the ACM certificate with the Certificate
+	CertificateDeletionPolicyDelete CertificateDeletionPolicy = "Delete"
+	// CertificateDeletionPolicyRetain keeps the ACM certificate, which is no longer managed
+	CertificateDeletionPolicyRetain CertificateDeletionPolicy = "Retain"
+)
+
 // CertificateSpec defines the desired state of Certificate
 // +k8s:openapi-gen=true
 type CertificateSpec struct {
@@ -46,6 +68,28 @@ type CertificateSpec struct {
 
 	// DNS Subject Alternative Names
 	SubjectAlternativeNames []string `json:"subjectAlternativeNames,omitempty"`
+
+	// Algorithm of the certificate key pair. ACM uses RSA_2048 when not set
+	// +optional
+	KeyAlgorithm CertificateKeyAlgorithm `json:"keyAlgorithm,omitempty"`
+
+	// Tags added to the ACM certificate. Keys starting with acm-manager/ are reserved
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// What happens to the ACM certificate when the Certificate is deleted. Defaults to Delete
+	// +optional
+	DeletionPolicy CertificateDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// IssuerReference is the issuer a v1beta1 Certificate was requested with
+type IssuerReference struct {
+	// Name of the issuer
+	Name string `json:"name"`
+
+	// Kind of the issuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
 }
 
 // CertificateStatus defines the observed state of Certificate
@@ -65,12 +109,40 @@ type CertificateStatus struct {
 
 	// Certificate not after date
 	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// Hash of the spec that produced the current ACM certificate
+	SpecHash string `json:"specHash,omitempty"`
+
+	// Last time the ACM certificate was compared with the spec
+	LastVerifiedTime *metav1.Time `json:"lastVerifiedTime,omitempty"`
+
+	// Hash of a spec change waiting for the settle window to elapse before
+	// a new ACM certificate is requested
+	PendingSpecHash string `json:"pendingSpecHash,omitempty"`
+
+	// Time the pending spec change was first observed
+	PendingSpecSince *metav1.Time `json:"pendingSpecSince,omitempty"`
+
+	// Issuer the current ACM certificate was requested with, the ACM
+	// certificate is replaced when the issuer changes
+	RequestedIssuerRef *IssuerReference `json:"requestedIssuerRef,omitempty"`
+
+	// Region of the spec the current ACM certificate was requested with, the
+	// ACM certificate is replaced when the region changes
+	RequestedRegion string `json:"requestedRegion,omitempty"`
+
+	// Conditions of the certificate
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+genclient
 //+k8s:openapi-gen=true
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
 //+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
 //+kubebuilder:printcolumn:name="NotBefore",type=string,JSONPath=`.status.notBefore`
 //+kubebuilder:printcolumn:name="NotAfter",type=string,JSONPath=`.status.notAfter`
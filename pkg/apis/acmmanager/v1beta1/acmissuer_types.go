@@ -0,0 +1,175 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// IssuerKind is the kind of the namespaced issuer
+	IssuerKind = "ACMIssuer"
+	// ClusterIssuerKind is the kind of the cluster scoped issuer
+	ClusterIssuerKind = "ClusterACMIssuer"
+)
+
+const (
+	// IssuerConditionReady indicates that the credentials of the issuer work
+	IssuerConditionReady = "Ready"
+)
+
+const (
+	IssuerReasonVerified         = "Verified"
+	IssuerReasonCredentialsError = "CredentialsError"
+	IssuerReasonRoleNotAllowed   = "RoleNotAllowed"
+)
+
+// ACMIssuerSpec defines the AWS account and region where the ACM certificates
+// of the Certificates referencing the issuer are requested
+// +k8s:openapi-gen=true
+type ACMIssuerSpec struct {
+	// AWS region of the ACM certificates. Defaults to the region of the controller
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// IAM role assumed to call ACM. The controller credentials are used when not set.
+	// The role of an ACMIssuer must be allowed by the controller and is assumed
+	// with the namespace of the issuer as external ID
+	// +kubebuilder:validation:Pattern=`^arn:aws[a-z-]*:iam::[0-9]{12}:role/.+$`
+	// +optional
+	RoleARN string `json:"roleARN,omitempty"`
+
+	// ARN of an AWS Private CA issuing private certificates instead of public ones.
+	// Private certificates do not need a domain validation
+	// +kubebuilder:validation:Pattern=`^arn:aws[a-z-]*:acm-pca:[a-z0-9-]+:[0-9]{12}:certificate-authority/.+$`
+	// +optional
+	PrivateCAARN string `json:"privateCAARN,omitempty"`
+
+	// Tags added to every ACM certificate of the issuer. Tags of the Certificate take precedence
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// ACMIssuerStatus defines the observed state of an issuer
+// +k8s:openapi-gen=true
+type ACMIssuerStatus struct {
+	// AWS account of the issuer credentials
+	// +optional
+	Account string `json:"account,omitempty"`
+
+	// Last time the credentials of the issuer were verified
+	// +optional
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
+
+	// Conditions of the issuer
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+genclient
+//+k8s:openapi-gen=true
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Account",type=string,JSONPath=`.status.account`
+//+kubebuilder:printcolumn:name="Region",type=string,JSONPath=`.spec.region`
+//+kubebuilder:printcolumn:name="Role",type=string,JSONPath=`.spec.roleARN`,priority=1
+
+// ACMIssuer requests the ACM certificates of the Certificates of its namespace
+type ACMIssuer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ACMIssuerSpec   `json:"spec,omitempty"`
+	Status ACMIssuerStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ACMIssuerList contains a list of ACMIssuer
+// +k8s:openapi-gen=true
+type ACMIssuerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ACMIssuer `json:"items"`
+}
+
+//+genclient
+//+genclient:nonNamespaced
+//+k8s:openapi-gen=true
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Account",type=string,JSONPath=`.status.account`
+//+kubebuilder:printcolumn:name="Region",type=string,JSONPath=`.spec.region`
+//+kubebuilder:printcolumn:name="Role",type=string,JSONPath=`.spec.roleARN`,priority=1
+
+// ClusterACMIssuer requests the ACM certificates of Certificates of any namespace
+type ClusterACMIssuer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ACMIssuerSpec   `json:"spec,omitempty"`
+	Status ACMIssuerStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterACMIssuerList contains a list of ClusterACMIssuer
+// +k8s:openapi-gen=true
+type ClusterACMIssuerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterACMIssuer `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ACMIssuer{}, &ACMIssuerList{}, &ClusterACMIssuer{}, &ClusterACMIssuerList{})
+}
+
+// GenericIssuer is implemented by both issuer kinds.
+// +k8s:deepcopy-gen=false
+type GenericIssuer interface {
+	metav1.Object
+	GetSpec() *ACMIssuerSpec
+	GetStatus() *ACMIssuerStatus
+}
+
+// GetSpec returns the spec of the issuer.
+func (i *ACMIssuer) GetSpec() *ACMIssuerSpec { return &i.Spec }
+
+// GetStatus returns the status of the issuer.
+func (i *ACMIssuer) GetStatus() *ACMIssuerStatus { return &i.Status }
+
+// GetSpec returns the spec of the issuer.
+func (i *ClusterACMIssuer) GetSpec() *ACMIssuerSpec { return &i.Spec }
+
+// GetStatus returns the status of the issuer.
+func (i *ClusterACMIssuer) GetStatus() *ACMIssuerStatus { return &i.Status }
+
+// IsReady returns true when the credentials of the issuer were verified.
+func (s *ACMIssuerStatus) IsReady() bool {
+	for _, c := range s.Conditions {
+		if c.Type == IssuerConditionReady {
+			return c.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CertificateStatusType string
+
+const (
+	CertificateStatusPendingValidation  CertificateStatusType = "PendingValidation"
+	CertificateStatusIssued             CertificateStatusType = "Issued"
+	CertificateStatusInactive           CertificateStatusType = "Inactive"
+	CertificateStatusExpired            CertificateStatusType = "Expired"
+	CertificateStatusValidationTimedOut CertificateStatusType = "ValidationTimedOut"
+	CertificateStatusRevoked            CertificateStatusType = "Revoked"
+	CertificateStatusFailed             CertificateStatusType = "Failed"
+	CertificateStatusUnknown            CertificateStatusType = "Unknown"
+	CertificateStatusError              CertificateStatusType = "Error"
+	CertificateStatusRequested          CertificateStatusType = "Requested"
+)
+
+const (
+	// CertificateConditionReady indicates that the ACM certificate is issued
+	CertificateConditionReady = "Ready"
+)
+
+const (
+	CertificateReasonIssued                = "Issued"
+	CertificateReasonPending               = "Pending"
+	CertificateReasonRequestFailed         = "RequestFailed"
+	CertificateReasonRequestBudgetExceeded = "RequestBudgetExceeded"
+	CertificateReasonInvalidRequest        = "InvalidRequest"
+	CertificateReasonPermissionDenied      = "PermissionDenied"
+	CertificateReasonThrottled             = "Throttled"
+	CertificateReasonIssuerNotReady        = "IssuerNotReady"
+	CertificateReasonClassNotFound         = "CertificateClassNotFound"
+	CertificateReasonPolicyDenied          = "PolicyDenied"
+)
+
+// CertificateKeyAlgorithm is the algorithm of the key pair of the ACM certificate
+// +kubebuilder:validation:Enum=RSA_2048;EC_prime256v1;EC_secp384r1
+type CertificateKeyAlgorithm string
+
+const (
+	CertificateKeyAlgorithmRSA2048      CertificateKeyAlgorithm = "RSA_2048"
+	CertificateKeyAlgorithmECPrime256v1 CertificateKeyAlgorithm = "EC_prime256v1"
+	CertificateKeyAlgorithmECSecp384r1  CertificateKeyAlgorithm = "EC_secp384r1"
+)
+
+// CertificateDeletionPolicy tells what happens to the ACM certificate when the
+// Certificate is deleted
+// +kubebuilder:validation:Enum=Delete;Retain
+type CertificateDeletionPolicy string
+
+const (
+	// CertificateDeletionPolicyDelete deletes the ACM certificate with the Certificate
+	CertificateDeletionPolicyDelete CertificateDeletionPolicy = "Delete"
+	// CertificateDeletionPolicyRetain keeps the ACM certificate, which is no longer managed
+	CertificateDeletionPolicyRetain CertificateDeletionPolicy = "Retain"
+)
+
+// CertificateValidationMethod is how the domain ownership is validated by ACM
+// +kubebuilder:validation:Enum=DNS;EMAIL
+type CertificateValidationMethod string
+
+const (
+	CertificateValidationMethodDNS   CertificateValidationMethod = "DNS"
+	CertificateValidationMethodEmail CertificateValidationMethod = "EMAIL"
+)
+
+// CertificateTransparencyLogging tells if the certificate is recorded in public
+// certificate transparency logs
+// +kubebuilder:validation:Enum=Enabled;Disabled
+type CertificateTransparencyLogging string
+
+const (
+	CertificateTransparencyLoggingEnabled  CertificateTransparencyLogging = "Enabled"
+	CertificateTransparencyLoggingDisabled CertificateTransparencyLogging = "Disabled"
+)
+
+// CertificateValidation configures the validation of the domain names
+type CertificateValidation struct {
+	// Validation method. DNS validation records are published with DNSEndpoints,
+	// EMAIL validation waits for the domain owner to approve the request. Defaults to DNS
+	// +optional
+	Method CertificateValidationMethod `json:"method,omitempty"`
+}
+
+// CertificateOptions configures how the ACM certificate is requested
+type CertificateOptions struct {
+	// Algorithm of the certificate key pair. ACM uses RSA_2048 when not set
+	// +optional
+	KeyAlgorithm CertificateKeyAlgorithm `json:"keyAlgorithm,omitempty"`
+
+	// Certificate transparency logging preference. ACM enables it when not set
+	// +optional
+	CertificateTransparencyLogging CertificateTransparencyLogging `json:"certificateTransparencyLogging,omitempty"`
+}
+
+// CertificateExport configures the export of the ACM certificate
+type CertificateExport struct {
+	// Request an exportable ACM certificate whose private key can be exported
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// IssuerReference selects the issuer of a Certificate
+type IssuerReference struct {
+	// Name of the issuer
+	Name string `json:"name"`
+
+	// Kind of the issuer, ACMIssuer in the namespace of the Certificate or ClusterACMIssuer
+	// +kubebuilder:validation:Enum=ACMIssuer;ClusterACMIssuer
+	// +kubebuilder:default=ACMIssuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+}
+
+// CertificateSpec defines the desired state of Certificate
+// +k8s:openapi-gen=true
+type CertificateSpec struct {
+	//+kubebuilder:validation:MaxLength=64
+	//+kubebuilder:validation:Required
+	//+kubebuilder:validation:Pattern=`^(\*\.)?(([A-Za-z0-9-]{0,62}[A-Za-z0-9])\.)+([A-Za-z0-9-]{1,62}[A-Za-z0-9])$`
+	// DNS Common Name
+	CommonName string `json:"commonName"`
+
+	// DNS Subject Alternative Names
+	SubjectAlternativeNames []string `json:"subjectAlternativeNames,omitempty"`
+
+	// Issuer of the ACM certificate. The credentials and region of the controller are used when not set
+	// +optional
+	IssuerRef *IssuerReference `json:"issuerRef,omitempty"`
+
+	// Name of the CertificateClass providing the options not set on the Certificate.
+	// The default class is used when not set
+	// +optional
+	CertificateClassName string `json:"certificateClassName,omitempty"`
+
+	// AWS region of the ACM certificate. Defaults to the region of the issuer, then of the controller
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Validation of the domain names
+	// +optional
+	Validation *CertificateValidation `json:"validation,omitempty"`
+
+	// Options of the certificate request
+	// +optional
+	Options *CertificateOptions `json:"options,omitempty"`
+
+	// Export of the certificate
+	// +optional
+	Export *CertificateExport `json:"export,omitempty"`
+
+	// Tags added to the ACM certificate. Keys starting with acm-manager/ are reserved
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// What happens to the ACM certificate when the Certificate is deleted. Defaults to Delete
+	// +optional
+	DeletionPolicy CertificateDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// CertificateStatus defines the observed state of Certificate
+// +k8s:openapi-gen=true
+type CertificateStatus struct {
+	// Certificate ARN
+	CertificateArn string `json:"certificateArn,omitempty"`
+
+	// Resource Records for DNS validation
+	ResourceRecords []ResourceRecord `json:"resourceRecords,omitempty"`
+
+	// Certificate status
+	Status CertificateStatusType `json:"status,omitempty"`
+
+	// Certificate not before date
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+
+	// Certificate not after date
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// Hash of the spec that produced the current ACM certificate
+	SpecHash string `json:"specHash,omitempty"`
+
+	// Last time the ACM certificate was compared with the spec
+	LastVerifiedTime *metav1.Time `json:"lastVerifiedTime,omitempty"`
+
+	// Hash of a spec change waiting for the settle window to elapse before
+	// a new ACM certificate is requested
+	PendingSpecHash string `json:"pendingSpecHash,omitempty"`
+
+	// Time the pending spec change was first observed
+	PendingSpecSince *metav1.Time `json:"pendingSpecSince,omitempty"`
+
+	// Issuer the current ACM certificate was requested with, the ACM
+	// certificate is replaced when the issuer changes
+	RequestedIssuerRef *IssuerReference `json:"requestedIssuerRef,omitempty"`
+
+	// Region of the spec the current ACM certificate was requested with, the
+	// ACM certificate is replaced when the region changes
+	RequestedRegion string `json:"requestedRegion,omitempty"`
+
+	// Conditions of the certificate
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+genclient
+//+k8s:openapi-gen=true
+//+kubebuilder:object:root=true
+//+kubebuilder:storageversion
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
+//+kubebuilder:printcolumn:name="NotBefore",type=string,JSONPath=`.status.notBefore`
+//+kubebuilder:printcolumn:name="NotAfter",type=string,JSONPath=`.status.notAfter`
+
+// Certificate is the Schema for the certificates API
+type Certificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CertificateSpec   `json:"spec,omitempty"`
+	Status CertificateStatus `json:"status,omitempty"`
+}
+
+// Hub marks this version as the conversion hub.
+func (*Certificate) Hub() {}
+
+//+kubebuilder:object:root=true
+
+// CertificateList contains a list of Certificate
+// +k8s:openapi-gen=true
+type CertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Certificate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Certificate{}, &CertificateList{})
+}
+
+type ResourceRecord struct {
+	// Name
+	Name string `json:"name"`
+
+	// The type of DNS record. Currently this can be CNAME.
+	Type string `json:"type"`
+
+	// The value of the CNAME record to add to DNS
+	Value string `json:"value"`
+}
+
+// KeyAlgorithm returns the key algorithm of the options, if any.
+func (s *CertificateSpec) KeyAlgorithm() CertificateKeyAlgorithm {
+	if s.Options == nil {
+		return ""
+	}
+	return s.Options.KeyAlgorithm
+}
+
+// ValidationMethod returns the validation method, DNS when not set.
+func (s *CertificateSpec) ValidationMethod() CertificateValidationMethod {
+	if s.Validation == nil || s.Validation.Method == "" {
+		return CertificateValidationMethodDNS
+	}
+	return s.Validation.Method
+}
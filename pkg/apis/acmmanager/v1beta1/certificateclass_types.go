@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultCertificateClassAnnotation set to "true" on a CertificateClass makes
+// it the class of the Certificates that do not name one
+const DefaultCertificateClassAnnotation = "certificateclass.acm-manager.io/is-default-class"
+
+// CertificateClassSpec holds the certificate options shared by the Certificates
+// of a class. Fields set on a Certificate take precedence.
+// +k8s:openapi-gen=true
+type CertificateClassSpec struct {
+	// Validation of the domain names
+	// +optional
+	Validation *CertificateValidation `json:"validation,omitempty"`
+
+	// Options of the certificate request
+	// +optional
+	Options *CertificateOptions `json:"options,omitempty"`
+
+	// Export of the certificate
+	// +optional
+	Export *CertificateExport `json:"export,omitempty"`
+
+	// Tags added to the ACM certificates. Tags of the Certificate take precedence
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// What happens to the ACM certificate when the Certificate is deleted
+	// +optional
+	DeletionPolicy CertificateDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+//+genclient
+//+genclient:nonNamespaced
+//+k8s:openapi-gen=true
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Default",type=string,JSONPath=`.metadata.annotations.certificateclass\.acm-manager\.io/is-default-class`
+//+kubebuilder:printcolumn:name="KeyAlgorithm",type=string,JSONPath=`.spec.options.keyAlgorithm`
+//+kubebuilder:printcolumn:name="DeletionPolicy",type=string,JSONPath=`.spec.deletionPolicy`
+
+// CertificateClass is a named profile of certificate options
+type CertificateClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CertificateClassSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CertificateClassList contains a list of CertificateClass
+// +k8s:openapi-gen=true
+type CertificateClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CertificateClass `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CertificateClass{}, &CertificateClassList{})
+}
+
+// IsDefault returns true when the class is the default one.
+func (c *CertificateClass) IsDefault() bool {
+	return c.Annotations[DefaultCertificateClassAnnotation] == "true"
+}
+
+// ApplyTo fills the fields of a Certificate spec that are not set with the
+// ones of the class.
+func (c *CertificateClassSpec) ApplyTo(spec *CertificateSpec) {
+	if c.Validation != nil && c.Validation.Method != "" && (spec.Validation == nil || spec.Validation.Method == "") {
+		spec.Validation = &CertificateValidation{Method: c.Validation.Method}
+	}
+	if c.Options != nil {
+		if spec.Options == nil {
+			spec.Options = &CertificateOptions{}
+		}
+		if spec.Options.KeyAlgorithm == "" {
+			spec.Options.KeyAlgorithm = c.Options.KeyAlgorithm
+		}
+		if spec.Options.CertificateTransparencyLogging == "" {
+			spec.Options.CertificateTransparencyLogging = c.Options.CertificateTransparencyLogging
+		}
+	}
+	if c.Export != nil && spec.Export == nil {
+		spec.Export = c.Export.DeepCopy()
+	}
+	for k, v := range c.Tags {
+		if _, ok := spec.Tags[k]; ok {
+			continue
+		}
+		if spec.Tags == nil {
+			spec.Tags = map[string]string{}
+		}
+		spec.Tags[k] = v
+	}
+	if spec.DeletionPolicy == "" {
+		spec.DeletionPolicy = c.DeletionPolicy
+	}
+}
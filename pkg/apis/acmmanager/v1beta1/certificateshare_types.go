@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CertificateShareFrom is a namespace whose Ingresses can reference the Certificates
+type CertificateShareFrom struct {
+	// Namespace of the Ingresses
+	Namespace string `json:"namespace"`
+}
+
+// CertificateShareTo is a Certificate of the namespace of the share that can be referenced
+type CertificateShareTo struct {
+	// Name of the Certificate. All the Certificates of the namespace when empty
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// CertificateShareSpec defines the Ingresses of other namespaces that can
+// reference the Certificates of the namespace of the share
+// +k8s:openapi-gen=true
+type CertificateShareSpec struct {
+	// Namespaces whose Ingresses can reference the Certificates
+	// +kubebuilder:validation:MinItems=1
+	From []CertificateShareFrom `json:"from"`
+
+	// Certificates that can be referenced
+	// +kubebuilder:validation:MinItems=1
+	To []CertificateShareTo `json:"to"`
+}
+
+//+genclient
+//+k8s:openapi-gen=true
+//+kubebuilder:object:root=true
+
+// CertificateShare allows the Ingresses of other namespaces to reference
+// Certificates of its namespace with the acm-manager.io/certificate-ref
+// annotation, like a Gateway API ReferenceGrant
+type CertificateShare struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CertificateShareSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CertificateShareList contains a list of CertificateShare
+// +k8s:openapi-gen=true
+type CertificateShareList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CertificateShare `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CertificateShare{}, &CertificateShareList{})
+}
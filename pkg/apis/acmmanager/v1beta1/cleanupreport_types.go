@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CleanupReportStatus is the outcome of the last orphaned ACM certificates cleanup run
+// +k8s:openapi-gen=true
+type CleanupReportStatus struct {
+	// Time the last cleanup run started
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+
+	// Duration of the last cleanup run
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// The last cleanup run did not change anything in ACM
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Number of ACM certificates inspected
+	Scanned int32 `json:"scanned"`
+
+	// Number of ACM certificates owned by this manager
+	Owned int32 `json:"owned"`
+
+	// Number of owned ACM certificates whose Certificate resource does not exist anymore
+	Orphaned int32 `json:"orphaned"`
+
+	// Number of orphaned ACM certificates deleted
+	Deleted int32 `json:"deleted"`
+
+	// Number of ACM certificates that could not be processed
+	Failed int32 `json:"failed"`
+
+	// Error that interrupted the last cleanup run
+	Error string `json:"error,omitempty"`
+}
+
+//+genclient
+//+genclient:nonNamespaced
+//+k8s:openapi-gen=true
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="LastRun",type=date,JSONPath=`.status.lastRunTime`
+//+kubebuilder:printcolumn:name="Scanned",type=integer,JSONPath=`.status.scanned`
+//+kubebuilder:printcolumn:name="Owned",type=integer,JSONPath=`.status.owned`
+//+kubebuilder:printcolumn:name="Orphaned",type=integer,JSONPath=`.status.orphaned`
+//+kubebuilder:printcolumn:name="Deleted",type=integer,JSONPath=`.status.deleted`
+//+kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failed`
+//+kubebuilder:printcolumn:name="DryRun",type=boolean,JSONPath=`.status.dryRun`,priority=1
+
+// CleanupReport exposes the result of the orphaned ACM certificates cleanup job
+type CleanupReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status CleanupReportStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CleanupReportList contains a list of CleanupReport
+// +k8s:openapi-gen=true
+type CleanupReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CleanupReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CleanupReport{}, &CleanupReportList{})
+}
@@ -0,0 +1,6 @@
+// Package v1beta1 contains API Schema definitions for the system v1beta1 API group
+// FIXME: https://github.com/kubernetes/code-generator/issues/150
+// +k8s:deepcopy-gen=package
+// +groupName=acm-manager.io
+// +groupGoName=Acmmanager
+package v1beta1
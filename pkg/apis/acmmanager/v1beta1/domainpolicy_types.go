@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DomainPolicySpec defines the domain names the Certificates of a set of
+// namespaces can request
+// +k8s:openapi-gen=true
+type DomainPolicySpec struct {
+	// Namespaces the policy applies to. An empty selector selects all namespaces
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Domain name patterns the Certificates of the selected namespaces can request.
+	// A pattern is either a domain name, matched exactly, or *.<domain> matching
+	// any name below the domain, wildcard names included
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Pattern=`^(\*\.)?[^*]+$`
+	AllowedDomains []string `json:"allowedDomains"`
+
+	// Maximum number of Certificates in each selected namespace
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxCertificates *int32 `json:"maxCertificates,omitempty"`
+}
+
+//+genclient
+//+genclient:nonNamespaced
+//+k8s:openapi-gen=true
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="MaxCertificates",type=integer,JSONPath=`.spec.maxCertificates`
+
+// DomainPolicy restricts the domain names the Certificates of a set of namespaces can request
+type DomainPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DomainPolicySpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DomainPolicyList contains a list of DomainPolicy
+// +k8s:openapi-gen=true
+type DomainPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DomainPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DomainPolicy{}, &DomainPolicyList{})
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TrustBundleConditionReady indicates that the bundle is published
+	TrustBundleConditionReady = "Ready"
+)
+
+const (
+	TrustBundleReasonPublished   = "Published"
+	TrustBundleReasonFetchFailed = "FetchFailed"
+)
+
+// TrustBundleLabel is set on the ConfigMaps of a TrustBundle to the name of the bundle
+const TrustBundleLabel = "acm-manager.io/trust-bundle"
+
+// TrustBundleSource is a Private CA whose certificate chain is added to the bundle
+type TrustBundleSource struct {
+	// ClusterACMIssuer whose private CA and credentials are used
+	// +optional
+	ClusterACMIssuer string `json:"clusterACMIssuer,omitempty"`
+
+	// ARN of a Private CA read with the controller credentials
+	// +kubebuilder:validation:Pattern=`^arn:aws[a-z-]*:acm-pca:[a-z0-9-]+:[0-9]{12}:certificate-authority/.+$`
+	// +optional
+	PrivateCAARN string `json:"privateCAARN,omitempty"`
+}
+
+// TrustBundleTarget defines the ConfigMaps the bundle is published to
+type TrustBundleTarget struct {
+	// Name of the ConfigMaps
+	ConfigMapName string `json:"configMapName"`
+
+	// Key of the PEM bundle in the ConfigMaps. Defaults to ca.crt
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Namespaces the ConfigMaps are created in. An empty selector selects all namespaces
+	// +optional
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// TrustBundleSpec defines the Private CAs of a bundle and where it is published
+// +k8s:openapi-gen=true
+type TrustBundleSpec struct {
+	// Private CAs of the bundle
+	// +kubebuilder:validation:MinItems=1
+	Sources []TrustBundleSource `json:"sources"`
+
+	// ConfigMaps the bundle is published to
+	Target TrustBundleTarget `json:"target"`
+
+	// How long a CA certificate no longer returned by its Private CA stays in
+	// the bundle, so the certificates it issued are still trusted while the
+	// CA rotates. Expired CA certificates are always removed. Defaults to 30 days
+	// +optional
+	OverlapPeriod *metav1.Duration `json:"overlapPeriod,omitempty"`
+}
+
+// TrustBundleCertificate is a CA certificate of the bundle
+type TrustBundleCertificate struct {
+	// SHA-256 fingerprint of the certificate
+	Fingerprint string `json:"fingerprint"`
+
+	// PEM encoded certificate
+	Certificate string `json:"certificate"`
+
+	// Certificate not after date
+	NotAfter metav1.Time `json:"notAfter"`
+
+	// Last time the certificate was returned by its Private CA
+	LastSeenTime metav1.Time `json:"lastSeenTime"`
+}
+
+// TrustBundleStatus defines the observed state of a TrustBundle
+// +k8s:openapi-gen=true
+type TrustBundleStatus struct {
+	// CA certificates of the bundle, the ones of a previous CA included during the overlap period
+	// +optional
+	Certificates []TrustBundleCertificate `json:"certificates,omitempty"`
+
+	// Namespaces the bundle is published to
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Conditions of the bundle
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+genclient
+//+genclient:nonNamespaced
+//+k8s:openapi-gen=true
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="ConfigMap",type=string,JSONPath=`.spec.target.configMapName`
+
+// TrustBundle publishes the certificate chains of Private CAs to ConfigMaps
+type TrustBundle struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TrustBundleSpec   `json:"spec,omitempty"`
+	Status TrustBundleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TrustBundleList contains a list of TrustBundle
+// +k8s:openapi-gen=true
+type TrustBundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TrustBundle `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TrustBundle{}, &TrustBundleList{})
+}
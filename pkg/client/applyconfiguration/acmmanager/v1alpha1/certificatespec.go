@@ -18,6 +18,10 @@ limitations under the License.
 
 package v1alpha1
 
+import (
+	acmmanagerv1alpha1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1alpha1"
+)
+
 // CertificateSpecApplyConfiguration represents a declarative configuration of the CertificateSpec type for use
 // with apply.
 //
@@ -27,6 +31,12 @@ type CertificateSpecApplyConfiguration struct {
 	CommonName *string `json:"commonName,omitempty"`
 	// DNS Subject Alternative Names
 	SubjectAlternativeNames []string `json:"subjectAlternativeNames,omitempty"`
+	// Algorithm of the certificate key pair. ACM uses RSA_2048 when not set
+	KeyAlgorithm *acmmanagerv1alpha1.CertificateKeyAlgorithm `json:"keyAlgorithm,omitempty"`
+	// Tags added to the ACM certificate. Keys starting with acm-manager/ are reserved
+	Tags map[string]string `json:"tags,omitempty"`
+	// What happens to the ACM certificate when the Certificate is deleted. Defaults to Delete
+	DeletionPolicy *acmmanagerv1alpha1.CertificateDeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // CertificateSpecApplyConfiguration constructs a declarative configuration of the CertificateSpec type for use with
@@ -52,3 +62,33 @@ func (b *CertificateSpecApplyConfiguration) WithSubjectAlternativeNames(values .
 	}
 	return b
 }
+
+// WithKeyAlgorithm sets the KeyAlgorithm field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the KeyAlgorithm field is set to the value of the last call.
+func (b *CertificateSpecApplyConfiguration) WithKeyAlgorithm(value acmmanagerv1alpha1.CertificateKeyAlgorithm) *CertificateSpecApplyConfiguration {
+	b.KeyAlgorithm = &value
+	return b
+}
+
+// WithTags puts the entries into the Tags field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, the entries provided by each call will be put on the Tags field,
+// overwriting an existing map entries in Tags field with the same key.
+func (b *CertificateSpecApplyConfiguration) WithTags(entries map[string]string) *CertificateSpecApplyConfiguration {
+	if b.Tags == nil && len(entries) > 0 {
+		b.Tags = make(map[string]string, len(entries))
+	}
+	for k, v := range entries {
+		b.Tags[k] = v
+	}
+	return b
+}
+
+// WithDeletionPolicy sets the DeletionPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DeletionPolicy field is set to the value of the last call.
+func (b *CertificateSpecApplyConfiguration) WithDeletionPolicy(value acmmanagerv1alpha1.CertificateDeletionPolicy) *CertificateSpecApplyConfiguration {
+	b.DeletionPolicy = &value
+	return b
+}
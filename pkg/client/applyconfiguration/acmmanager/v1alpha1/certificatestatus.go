@@ -22,6 +22,7 @@ import (
 	acmmanagerv1alpha1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1alpha1"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1 "k8s.io/client-go/applyconfigurations/meta/v1"
 )
 
 // CertificateStatusApplyConfiguration represents a declarative configuration of the CertificateStatus type for use
@@ -39,6 +40,23 @@ type CertificateStatusApplyConfiguration struct {
 	NotBefore *v1.Time `json:"notBefore,omitempty"`
 	// Certificate not after date
 	NotAfter *v1.Time `json:"notAfter,omitempty"`
+	// Hash of the spec that produced the current ACM certificate
+	SpecHash *string `json:"specHash,omitempty"`
+	// Last time the ACM certificate was compared with the spec
+	LastVerifiedTime *v1.Time `json:"lastVerifiedTime,omitempty"`
+	// Hash of a spec change waiting for the settle window to elapse before
+	// a new ACM certificate is requested
+	PendingSpecHash *string `json:"pendingSpecHash,omitempty"`
+	// Time the pending spec change was first observed
+	PendingSpecSince *v1.Time `json:"pendingSpecSince,omitempty"`
+	// Issuer the current ACM certificate was requested with, the ACM
+	// certificate is replaced when the issuer changes
+	RequestedIssuerRef *IssuerReferenceApplyConfiguration `json:"requestedIssuerRef,omitempty"`
+	// Region of the spec the current ACM certificate was requested with, the
+	// ACM certificate is replaced when the region changes
+	RequestedRegion *string `json:"requestedRegion,omitempty"`
+	// Conditions of the certificate
+	Conditions []metav1.ConditionApplyConfiguration `json:"conditions,omitempty"`
 }
 
 // CertificateStatusApplyConfiguration constructs a declarative configuration of the CertificateStatus type for use with
@@ -91,3 +109,64 @@ func (b *CertificateStatusApplyConfiguration) WithNotAfter(value v1.Time) *Certi
 	b.NotAfter = &value
 	return b
 }
+
+// WithSpecHash sets the SpecHash field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SpecHash field is set to the value of the last call.
+func (b *CertificateStatusApplyConfiguration) WithSpecHash(value string) *CertificateStatusApplyConfiguration {
+	b.SpecHash = &value
+	return b
+}
+
+// WithLastVerifiedTime sets the LastVerifiedTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastVerifiedTime field is set to the value of the last call.
+func (b *CertificateStatusApplyConfiguration) WithLastVerifiedTime(value v1.Time) *CertificateStatusApplyConfiguration {
+	b.LastVerifiedTime = &value
+	return b
+}
+
+// WithPendingSpecHash sets the PendingSpecHash field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PendingSpecHash field is set to the value of the last call.
+func (b *CertificateStatusApplyConfiguration) WithPendingSpecHash(value string) *CertificateStatusApplyConfiguration {
+	b.PendingSpecHash = &value
+	return b
+}
+
+// WithPendingSpecSince sets the PendingSpecSince field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PendingSpecSince field is set to the value of the last call.
+func (b *CertificateStatusApplyConfiguration) WithPendingSpecSince(value v1.Time) *CertificateStatusApplyConfiguration {
+	b.PendingSpecSince = &value
+	return b
+}
+
+// WithRequestedIssuerRef sets the RequestedIssuerRef field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RequestedIssuerRef field is set to the value of the last call.
+func (b *CertificateStatusApplyConfiguration) WithRequestedIssuerRef(value *IssuerReferenceApplyConfiguration) *CertificateStatusApplyConfiguration {
+	b.RequestedIssuerRef = value
+	return b
+}
+
+// WithRequestedRegion sets the RequestedRegion field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RequestedRegion field is set to the value of the last call.
+func (b *CertificateStatusApplyConfiguration) WithRequestedRegion(value string) *CertificateStatusApplyConfiguration {
+	b.RequestedRegion = &value
+	return b
+}
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *CertificateStatusApplyConfiguration) WithConditions(values ...*metav1.ConditionApplyConfiguration) *CertificateStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithConditions")
+		}
+		b.Conditions = append(b.Conditions, *values[i])
+	}
+	return b
+}
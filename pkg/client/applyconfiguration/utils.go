@@ -20,7 +20,9 @@ package applyconfiguration
 
 import (
 	v1alpha1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1alpha1"
+	v1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
 	acmmanagerv1alpha1 "vdesjardins/acm-manager/pkg/client/applyconfiguration/acmmanager/v1alpha1"
+	acmmanagerv1beta1 "vdesjardins/acm-manager/pkg/client/applyconfiguration/acmmanager/v1beta1"
 	internal "vdesjardins/acm-manager/pkg/client/applyconfiguration/internal"
 
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -39,9 +41,69 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &acmmanagerv1alpha1.CertificateSpecApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("CertificateStatus"):
 		return &acmmanagerv1alpha1.CertificateStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("IssuerReference"):
+		return &acmmanagerv1alpha1.IssuerReferenceApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("ResourceRecord"):
 		return &acmmanagerv1alpha1.ResourceRecordApplyConfiguration{}
 
+		// Group=acm-manager.io, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithKind("ACMIssuer"):
+		return &acmmanagerv1beta1.ACMIssuerApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ACMIssuerSpec"):
+		return &acmmanagerv1beta1.ACMIssuerSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ACMIssuerStatus"):
+		return &acmmanagerv1beta1.ACMIssuerStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("Certificate"):
+		return &acmmanagerv1beta1.CertificateApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CertificateClass"):
+		return &acmmanagerv1beta1.CertificateClassApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CertificateClassSpec"):
+		return &acmmanagerv1beta1.CertificateClassSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CertificateExport"):
+		return &acmmanagerv1beta1.CertificateExportApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CertificateOptions"):
+		return &acmmanagerv1beta1.CertificateOptionsApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CertificateShare"):
+		return &acmmanagerv1beta1.CertificateShareApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CertificateShareFrom"):
+		return &acmmanagerv1beta1.CertificateShareFromApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CertificateShareSpec"):
+		return &acmmanagerv1beta1.CertificateShareSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CertificateShareTo"):
+		return &acmmanagerv1beta1.CertificateShareToApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CertificateSpec"):
+		return &acmmanagerv1beta1.CertificateSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CertificateStatus"):
+		return &acmmanagerv1beta1.CertificateStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CertificateValidation"):
+		return &acmmanagerv1beta1.CertificateValidationApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CleanupReport"):
+		return &acmmanagerv1beta1.CleanupReportApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CleanupReportStatus"):
+		return &acmmanagerv1beta1.CleanupReportStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ClusterACMIssuer"):
+		return &acmmanagerv1beta1.ClusterACMIssuerApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("DomainPolicy"):
+		return &acmmanagerv1beta1.DomainPolicyApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("DomainPolicySpec"):
+		return &acmmanagerv1beta1.DomainPolicySpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("IssuerReference"):
+		return &acmmanagerv1beta1.IssuerReferenceApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ResourceRecord"):
+		return &acmmanagerv1beta1.ResourceRecordApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("TrustBundle"):
+		return &acmmanagerv1beta1.TrustBundleApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("TrustBundleCertificate"):
+		return &acmmanagerv1beta1.TrustBundleCertificateApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("TrustBundleSource"):
+		return &acmmanagerv1beta1.TrustBundleSourceApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("TrustBundleSpec"):
+		return &acmmanagerv1beta1.TrustBundleSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("TrustBundleStatus"):
+		return &acmmanagerv1beta1.TrustBundleStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("TrustBundleTarget"):
+		return &acmmanagerv1beta1.TrustBundleTargetApplyConfiguration{}
+
 	}
 	return nil
 }
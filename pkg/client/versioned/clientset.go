@@ -22,6 +22,7 @@ import (
 	fmt "fmt"
 	http "net/http"
 	acmmanagerv1alpha1 "vdesjardins/acm-manager/pkg/client/versioned/typed/acmmanager/v1alpha1"
+	acmmanagerv1beta1 "vdesjardins/acm-manager/pkg/client/versioned/typed/acmmanager/v1beta1"
 
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
@@ -31,12 +32,14 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	AcmmanagerV1alpha1() acmmanagerv1alpha1.AcmmanagerV1alpha1Interface
+	AcmmanagerV1beta1() acmmanagerv1beta1.AcmmanagerV1beta1Interface
 }
 
 // Clientset contains the clients for groups.
 type Clientset struct {
 	*discovery.DiscoveryClient
 	acmmanagerV1alpha1 *acmmanagerv1alpha1.AcmmanagerV1alpha1Client
+	acmmanagerV1beta1  *acmmanagerv1beta1.AcmmanagerV1beta1Client
 }
 
 // AcmmanagerV1alpha1 retrieves the AcmmanagerV1alpha1Client
@@ -44,6 +47,11 @@ func (c *Clientset) AcmmanagerV1alpha1() acmmanagerv1alpha1.AcmmanagerV1alpha1In
 	return c.acmmanagerV1alpha1
 }
 
+// AcmmanagerV1beta1 retrieves the AcmmanagerV1beta1Client
+func (c *Clientset) AcmmanagerV1beta1() acmmanagerv1beta1.AcmmanagerV1beta1Interface {
+	return c.acmmanagerV1beta1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -92,6 +100,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.acmmanagerV1beta1, err = acmmanagerv1beta1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -114,6 +126,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.acmmanagerV1alpha1 = acmmanagerv1alpha1.New(c)
+	cs.acmmanagerV1beta1 = acmmanagerv1beta1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
@@ -23,6 +23,8 @@ import (
 	clientset "vdesjardins/acm-manager/pkg/client/versioned"
 	acmmanagerv1alpha1 "vdesjardins/acm-manager/pkg/client/versioned/typed/acmmanager/v1alpha1"
 	fakeacmmanagerv1alpha1 "vdesjardins/acm-manager/pkg/client/versioned/typed/acmmanager/v1alpha1/fake"
+	acmmanagerv1beta1 "vdesjardins/acm-manager/pkg/client/versioned/typed/acmmanager/v1beta1"
+	fakeacmmanagerv1beta1 "vdesjardins/acm-manager/pkg/client/versioned/typed/acmmanager/v1beta1/fake"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -141,3 +143,8 @@ var (
 func (c *Clientset) AcmmanagerV1alpha1() acmmanagerv1alpha1.AcmmanagerV1alpha1Interface {
 	return &fakeacmmanagerv1alpha1.FakeAcmmanagerV1alpha1{Fake: &c.Fake}
 }
+
+// AcmmanagerV1beta1 retrieves the AcmmanagerV1beta1Client
+func (c *Clientset) AcmmanagerV1beta1() acmmanagerv1beta1.AcmmanagerV1beta1Interface {
+	return &fakeacmmanagerv1beta1.FakeAcmmanagerV1beta1{Fake: &c.Fake}
+}
@@ -20,6 +20,7 @@ package fake
 
 import (
 	acmmanagerv1alpha1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1alpha1"
+	acmmanagerv1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -33,6 +34,7 @@ var codecs = serializer.NewCodecFactory(scheme)
 
 var localSchemeBuilder = runtime.SchemeBuilder{
 	acmmanagerv1alpha1.AddToScheme,
+	acmmanagerv1beta1.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
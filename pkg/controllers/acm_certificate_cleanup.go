@@ -0,0 +1,419 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	"vdesjardins/acm-manager/pkg/controllers/external_api_clients"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	acmtypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+const (
+	TagCertificateOrphanedSince = "acm-manager/orphaned-since"
+)
+
+const (
+	CleanupEventOrphanDetected = "OrphanDetected"
+	CleanupEventOrphanRestored = "OrphanRestored"
+	CleanupEventOrphanDeleted  = "OrphanDeleted"
+	CleanupEventOrphanDryRun   = "OrphanDryRun"
+	CleanupEventCleanupError   = "OrphanCleanupError"
+)
+
+// ACMCertificateCleanupJob periodically deletes ACM certificates owned by this
+// manager whose Certificate resource no longer exists, or was issued another
+// ACM certificate. An orphan is first tagged with the time it was detected and
+// is only deleted once GracePeriod has elapsed, which leaves room for a
+// Certificate to be restored from a backup. The outcome of each run is saved in
+// the status of a cluster scoped CleanupReport named after the manager owner
+// name.
+type ACMCertificateCleanupJob struct {
+	client.Client
+	ACMClient external_api_clients.AcmAWSAPI
+
+	// Clients creates the ACM clients of the regions and roles of the issuers
+	// and Certificates, which are scanned along with ACMClient. Nil only scans
+	// ACMClient.
+	Clients external_api_clients.ClientFactory
+
+	// Interval between two cleanup runs
+	Interval time.Duration
+
+	// GracePeriod is how long an orphan is kept before being deleted. A zero
+	// value deletes orphans as soon as they are detected.
+	GracePeriod time.Duration
+
+	// DryRun reports what would be tagged or deleted without changing anything in ACM
+	DryRun bool
+
+	recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=acm-manager.io,resources=cleanupreports,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=acm-manager.io,resources=cleanupreports/status,verbs=get;update;patch
+
+// SetupWithManager registers the cleanup job as a Runnable of the Manager.
+func (j *ACMCertificateCleanupJob) SetupWithManager(mgr ctrl.Manager) error {
+	j.recorder = mgr.GetEventRecorderFor("ACMCertificateCleanup")
+
+	return mgr.Add(j)
+}
+
+// NeedLeaderElection makes sure only the elected manager deletes certificates.
+func (j *ACMCertificateCleanupJob) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs the cleanup job at every Interval until the context is cancelled.
+func (j *ACMCertificateCleanupJob) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("background cleanup")
+	log.Info("starting ACM certificate cleanup job", "interval", j.Interval, "gracePeriod", j.GracePeriod, "dryRun", j.DryRun)
+
+	ticker := time.NewTicker(j.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("stopping ACM certificate cleanup job")
+			return nil
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *ACMCertificateCleanupJob) run(ctx context.Context) {
+	log := log.FromContext(ctx).WithName("background cleanup")
+
+	start := time.Now()
+	report, err := j.cleanupOrphanACMCertificates(ctx)
+	result := "success"
+	if err != nil {
+		log.Error(err, "cleanup run failed")
+		report.Error = err.Error()
+		result = "error"
+	}
+	report.LastRunTime = &metav1.Time{Time: start}
+	report.Duration = &metav1.Duration{Duration: time.Since(start).Round(time.Millisecond)}
+	report.DryRun = j.DryRun
+
+	cleanupRunsTotal.WithLabelValues(result).Inc()
+	cleanupRunDuration.Observe(time.Since(start).Seconds())
+	cleanupLastRunTimestamp.SetToCurrentTime()
+	cleanupCertificates.WithLabelValues("scanned").Set(float64(report.Scanned))
+	cleanupCertificates.WithLabelValues("owned").Set(float64(report.Owned))
+	cleanupCertificates.WithLabelValues("orphaned").Set(float64(report.Orphaned))
+	cleanupCertificates.WithLabelValues("deleted").Set(float64(report.Deleted))
+	cleanupCertificates.WithLabelValues("failed").Set(float64(report.Failed))
+
+	log.Info("cleanup run completed", "scanned", report.Scanned, "owned", report.Owned,
+		"orphaned", report.Orphaned, "deleted", report.Deleted, "failed", report.Failed)
+
+	if err := j.saveReport(ctx, report); err != nil {
+		log.Error(err, "unable to save cleanup report")
+	}
+}
+
+// acmLocation is a region and role ACM certificates are requested with, the
+// zero value being the ones of the controller.
+type acmLocation struct {
+	region string
+	role   external_api_clients.Role
+}
+
+func (l acmLocation) String() string {
+	return fmt.Sprintf("region %q and role %q", l.region, l.role.ARN)
+}
+
+func (j *ACMCertificateCleanupJob) cleanupOrphanACMCertificates(ctx context.Context) (certificatev1beta1.CleanupReportStatus, error) {
+	report := certificatev1beta1.CleanupReportStatus{}
+	now := time.Now()
+
+	locations, err := j.locations(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	// the ARN of a certificate reached through two locations of the same
+	// account and region is only scanned once
+	scanned := map[string]bool{}
+	var errs []error
+	for _, location := range locations {
+		acmClient := j.ACMClient
+		if location != (acmLocation{}) {
+			acmClient = j.Clients.ACM(location.region, location.role)
+		}
+
+		paginator := acm.NewListCertificatesPaginator(acmClient, &acm.ListCertificatesInput{})
+		for paginator.HasMorePages() {
+			output, err := paginator.NextPage(ctx)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("unable to list certificates of %s: %w", location, err))
+				break
+			}
+
+			for _, summary := range output.CertificateSummaryList {
+				if scanned[*summary.CertificateArn] {
+					continue
+				}
+				scanned[*summary.CertificateArn] = true
+				report.Scanned++
+				j.cleanupACMCertificate(ctx, acmClient, *summary.CertificateArn, now, &report)
+			}
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// locations returns the regions and roles the ACM certificates of the
+// Certificates are requested with: the ones of the controller, of the issuers,
+// and the regions of the Certificates overriding the one of their issuer. The
+// locations of deleted issuers are not known.
+func (j *ACMCertificateCleanupJob) locations(ctx context.Context) ([]acmLocation, error) {
+	locations := []acmLocation{{}}
+	if j.Clients == nil {
+		return locations, nil
+	}
+	add := func(location acmLocation) {
+		if !slices.Contains(locations, location) {
+			locations = append(locations, location)
+		}
+	}
+
+	issuers := map[types.NamespacedName]acmLocation{}
+	acmIssuers := &certificatev1beta1.ACMIssuerList{}
+	if err := j.List(ctx, acmIssuers); err != nil {
+		return nil, fmt.Errorf("unable to list issuers: %w", err)
+	}
+	for _, issuer := range acmIssuers.Items {
+		role, err := issuerRole(&issuer)
+		if err != nil {
+			// the role of the issuer is not assumed while it is not allowed
+			continue
+		}
+		location := acmLocation{region: issuer.Spec.Region, role: role}
+		issuers[client.ObjectKeyFromObject(&issuer)] = location
+		add(location)
+	}
+	clusterIssuers := &certificatev1beta1.ClusterACMIssuerList{}
+	if err := j.List(ctx, clusterIssuers); err != nil {
+		return nil, fmt.Errorf("unable to list cluster issuers: %w", err)
+	}
+	for _, issuer := range clusterIssuers.Items {
+		location := acmLocation{region: issuer.Spec.Region, role: external_api_clients.Role{ARN: issuer.Spec.RoleARN}}
+		issuers[client.ObjectKeyFromObject(&issuer)] = location
+		add(location)
+	}
+
+	certs := &certificatev1beta1.CertificateList{}
+	if err := j.List(ctx, certs); err != nil {
+		return nil, fmt.Errorf("unable to list certificates: %w", err)
+	}
+	for _, cert := range certs.Items {
+		// the recorded location is the one of the current ACM certificate,
+		// the spec one of the next request
+		for _, requested := range []struct {
+			ref    *certificatev1beta1.IssuerReference
+			region string
+		}{
+			{cert.Spec.IssuerRef, cert.Spec.Region},
+			{cert.Status.RequestedIssuerRef, cert.Status.RequestedRegion},
+		} {
+			if requested.region == "" {
+				continue
+			}
+			location := acmLocation{}
+			if requested.ref != nil {
+				key := types.NamespacedName{Name: requested.ref.Name}
+				if requested.ref.Kind != certificatev1beta1.ClusterIssuerKind {
+					key.Namespace = cert.Namespace
+				}
+				location = issuers[key]
+			}
+			location.region = requested.region
+			add(location)
+		}
+	}
+
+	return locations, nil
+}
+
+func (j *ACMCertificateCleanupJob) cleanupACMCertificate(ctx context.Context, acmClient external_api_clients.AcmAWSAPI, arn string, now time.Time, report *certificatev1beta1.CleanupReportStatus) {
+	log := log.FromContext(ctx).WithName("background cleanup").WithValues("ARN", arn)
+
+	output, err := acmClient.ListTagsForCertificate(ctx, &acm.ListTagsForCertificateInput{
+		CertificateArn: aws.String(arn),
+	})
+	if err != nil {
+		log.Error(err, "unable to list certificate tags")
+		report.Failed++
+		return
+	}
+
+	tags := make(map[string]string, len(output.Tags))
+	for _, t := range output.Tags {
+		tags[*t.Key] = *t.Value
+	}
+
+	if tags[TagCertificateOwner] != ACMManagerOwnerName {
+		return
+	}
+	report.Owned++
+
+	nsName := types.NamespacedName{Name: tags[TagCertificateName], Namespace: tags[TagCertificateNamespace]}
+	log = log.WithValues("certificate", nsName)
+
+	// reference used to attach events to the Certificate that owned the ACM certificate
+	ref := &certificatev1beta1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nsName.Name,
+			Namespace: nsName.Namespace,
+		},
+	}
+
+	cert := &certificatev1beta1.Certificate{}
+	err = j.Get(ctx, nsName, cert)
+	if err == nil && !supersededACMCertificate(cert, arn) {
+		// the Certificate came back during the grace period
+		if _, ok := tags[TagCertificateOrphanedSince]; ok && !j.DryRun {
+			if _, err := acmClient.RemoveTagsFromCertificate(ctx, &acm.RemoveTagsFromCertificateInput{
+				CertificateArn: aws.String(arn),
+				Tags:           []acmtypes.Tag{{Key: aws.String(TagCertificateOrphanedSince)}},
+			}); err != nil {
+				log.Error(err, "unable to remove orphaned tag from certificate")
+				report.Failed++
+				return
+			}
+			log.Info("certificate is no longer orphaned")
+			j.recorder.Event(ref, core.EventTypeNormal, CleanupEventOrphanRestored,
+				fmt.Sprintf("ACM certificate %s is no longer orphaned", arn))
+		}
+		return
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		// without knowing if the Certificate exists the ACM certificate is kept
+		log.Error(err, "unable to fetch Certificate owning the ACM certificate")
+		report.Failed++
+		return
+	}
+
+	report.Orphaned++
+
+	// a missing or unreadable tag means the orphan has just been detected
+	tagged := true
+	orphanedSince, err := time.Parse(time.RFC3339, tags[TagCertificateOrphanedSince])
+	if err != nil {
+		orphanedSince = now
+		tagged = false
+	}
+
+	if now.Sub(orphanedSince) < j.GracePeriod {
+		if tagged {
+			return
+		}
+		if j.DryRun {
+			log.Info("dry-run: certificate would be tagged as orphaned")
+			j.recorder.Event(ref, core.EventTypeNormal, CleanupEventOrphanDryRun,
+				fmt.Sprintf("dry-run: ACM certificate %s would be tagged as orphaned", arn))
+			return
+		}
+		if _, err := acmClient.AddTagsToCertificate(ctx, &acm.AddTagsToCertificateInput{
+			CertificateArn: aws.String(arn),
+			Tags: []acmtypes.Tag{{
+				Key:   aws.String(TagCertificateOrphanedSince),
+				Value: aws.String(now.UTC().Format(time.RFC3339)),
+			}},
+		}); err != nil {
+			log.Error(err, "unable to tag orphaned certificate")
+			j.recorder.Event(ref, core.EventTypeWarning, CleanupEventCleanupError, err.Error())
+			report.Failed++
+			return
+		}
+		log.Info("certificate tagged as orphaned", "deleteAfter", now.Add(j.GracePeriod))
+		j.recorder.Event(ref, core.EventTypeNormal, CleanupEventOrphanDetected,
+			fmt.Sprintf("ACM certificate %s is orphaned and will be deleted after %s", arn, now.Add(j.GracePeriod).UTC().Format(time.RFC3339)))
+		return
+	}
+
+	if j.DryRun {
+		log.Info("dry-run: certificate would be deleted in ACM")
+		j.recorder.Event(ref, core.EventTypeNormal, CleanupEventOrphanDryRun,
+			fmt.Sprintf("dry-run: ACM certificate %s would be deleted", arn))
+		return
+	}
+
+	if _, err := acmClient.DeleteCertificate(ctx, &acm.DeleteCertificateInput{
+		CertificateArn: aws.String(arn),
+	}); err != nil {
+		log.Error(err, "unable to delete unused owned certificate")
+		j.recorder.Event(ref, core.EventTypeWarning, CleanupEventCleanupError, err.Error())
+		report.Failed++
+		return
+	}
+	log.Info("certificate deleted in ACM")
+	j.recorder.Event(ref, core.EventTypeNormal, CleanupEventOrphanDeleted,
+		fmt.Sprintf("orphaned ACM certificate %s deleted", arn))
+	cleanupDeletedCertificatesTotal.Inc()
+	report.Deleted++
+}
+
+// supersededACMCertificate returns true when another ACM certificate of the
+// Certificate is issued, like the one requested in another account or region
+// after a change of its issuer or region.
+func supersededACMCertificate(cert *certificatev1beta1.Certificate, arn string) bool {
+	return cert.Status.CertificateArn != "" && cert.Status.CertificateArn != arn &&
+		cert.Status.Status == certificatev1beta1.CertificateStatusIssued
+}
+
+func (j *ACMCertificateCleanupJob) saveReport(ctx context.Context, status certificatev1beta1.CleanupReportStatus) error {
+	report := &certificatev1beta1.CleanupReport{}
+	if err := j.Get(ctx, types.NamespacedName{Name: ACMManagerOwnerName}, report); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("unable to fetch cleanup report: %w", err)
+		}
+		report.Name = ACMManagerOwnerName
+		if err := j.Create(ctx, report); err != nil {
+			return fmt.Errorf("unable to create cleanup report: %w", err)
+		}
+	}
+
+	report.Status = status
+	if err := j.Status().Update(ctx, report); err != nil {
+		return fmt.Errorf("unable to update cleanup report status: %w", err)
+	}
+
+	return nil
+}
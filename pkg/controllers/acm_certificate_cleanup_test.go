@@ -2,32 +2,29 @@ package controllers
 
 import (
 	"context"
+	"time"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/acm"
 	acmtypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	apiV1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1alpha1"
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
 	"vdesjardins/acm-manager/pkg/controllers/external_api_clients"
 )
 
 type acmClientCleanupMock struct {
-	deleteCalled bool
-}
-
-type certificateClientCleanupMock struct {
-	getCalled bool
-}
-
-func (c *certificateClientCleanupMock) GetCertificateForNamespace(ctx context.Context, namespace string, certificateName string, options metav1.GetOptions) (*apiV1.Certificate, error) {
-	c.getCalled = true
-
-	return nil, errors.NewNotFound(schema.GroupResource{Group: "unit-test.com", Resource: "unit-test"},
-		"unit-test")
+	deleteCalled     bool
+	addTagsCalled    bool
+	removeTagsCalled bool
+	orphanedSince    string
+	// arns are the listed certificates, test-arn when empty
+	arns []string
 }
 
 func (a *acmClientCleanupMock) DescribeCertificate(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
@@ -62,6 +59,13 @@ func (a *acmClientCleanupMock) DeleteCertificate(ctx context.Context, params *ac
 }
 
 func (a *acmClientCleanupMock) ListCertificates(ctx context.Context, params *acm.ListCertificatesInput, optFns ...func(*acm.Options)) (*acm.ListCertificatesOutput, error) {
+	if len(a.arns) > 0 {
+		output := &acm.ListCertificatesOutput{}
+		for _, arn := range a.arns {
+			output.CertificateSummaryList = append(output.CertificateSummaryList, acmtypes.CertificateSummary{CertificateArn: aws.String(arn)})
+		}
+		return output, nil
+	}
 	return &acm.ListCertificatesOutput{
 		CertificateSummaryList: []acmtypes.CertificateSummary{
 			{
@@ -72,47 +76,175 @@ func (a *acmClientCleanupMock) ListCertificates(ctx context.Context, params *acm
 }
 
 func (a *acmClientCleanupMock) ListTagsForCertificate(ctx context.Context, params *acm.ListTagsForCertificateInput, optFns ...func(*acm.Options)) (*acm.ListTagsForCertificateOutput, error) {
-	return &acm.ListTagsForCertificateOutput{
-		Tags: []acmtypes.Tag{
-			{
-				Key:   aws.String(TagCertificateName),
-				Value: aws.String("test-arn"),
-			},
-			{
-				Key:   aws.String(TagCertificateNamespace),
-				Value: aws.String("default"),
-			},
-			{
-				Key:   aws.String(TagCertificateOwner),
-				Value: aws.String(ACMManagerOwnerName),
-			},
+	tags := []acmtypes.Tag{
+		{
+			Key:   aws.String(TagCertificateName),
+			Value: aws.String("test-arn"),
 		},
-	}, nil
+		{
+			Key:   aws.String(TagCertificateNamespace),
+			Value: aws.String("default"),
+		},
+		{
+			Key:   aws.String(TagCertificateOwner),
+			Value: aws.String(ACMManagerOwnerName),
+		},
+	}
+	if a.orphanedSince != "" {
+		tags = append(tags, acmtypes.Tag{
+			Key:   aws.String(TagCertificateOrphanedSince),
+			Value: aws.String(a.orphanedSince),
+		})
+	}
+
+	return &acm.ListTagsForCertificateOutput{Tags: tags}, nil
 }
 
-var _ = Describe("ACM Certificate Cleanup Job", func() {
-	Context("clean missing certificate", func() {
-		defer GinkgoRecover()
+func (a *acmClientCleanupMock) AddTagsToCertificate(ctx context.Context, params *acm.AddTagsToCertificateInput, optFns ...func(*acm.Options)) (*acm.AddTagsToCertificateOutput, error) {
+	a.addTagsCalled = true
+	return &acm.AddTagsToCertificateOutput{}, nil
+}
 
-		acmClientMock := acmClientCleanupMock{}
-		Expect(acmClientMock.deleteCalled).To(BeFalse())
+func (a *acmClientCleanupMock) RemoveTagsFromCertificate(ctx context.Context, params *acm.RemoveTagsFromCertificateInput, optFns ...func(*acm.Options)) (*acm.RemoveTagsFromCertificateOutput, error) {
+	a.removeTagsCalled = true
+	return &acm.RemoveTagsFromCertificateOutput{}, nil
+}
 
-		oldACMClient := external_api_clients.NewAcmClient
-		external_api_clients.NewAcmClient = func(service *acm.Client) external_api_clients.AcmAWSAPI {
-			return &acmClientMock
-		}
-		defer func() { external_api_clients.NewAcmClient = oldACMClient }()
+// cleanupClientFactoryMock returns the ACM client of a region and role
+type cleanupClientFactoryMock struct {
+	clientFactoryMock
+	acm map[acmLocation]*acmClientCleanupMock
+}
 
-		certificateClientMock := certificateClientCleanupMock{}
-		Expect(certificateClientMock.getCalled).To(BeFalse())
-		oldCertificateRestClient := external_api_clients.NewCertificateRestClient
-		external_api_clients.NewCertificateRestClient = func(ctx context.Context) (external_api_clients.CertificateRestAPI, error) {
-			return &certificateClientMock, nil
+func (f *cleanupClientFactoryMock) ACM(region string, role external_api_clients.Role) external_api_clients.AcmAWSAPI {
+	return f.acm[acmLocation{region: region, role: role}]
+}
+
+var _ = Describe("ACM Certificate Cleanup Job", func() {
+	var (
+		acmClientMock *acmClientCleanupMock
+		job           *ACMCertificateCleanupJob
+	)
+
+	newJob := func(objs ...client.Object) *ACMCertificateCleanupJob {
+		return &ACMCertificateCleanupJob{
+			Client:      newFakeClient(objs...),
+			ACMClient:   acmClientMock,
+			GracePeriod: time.Hour,
+			recorder:    record.NewFakeRecorder(10),
 		}
-		defer func() { external_api_clients.NewCertificateRestClient = oldCertificateRestClient }()
+	}
+
+	BeforeEach(func() {
+		acmClientMock = &acmClientCleanupMock{}
+		job = newJob()
+	})
+
+	Context("clean missing certificate", func() {
+		It("Should tag a newly detected orphan without deleting it", func() {
+			report, err := job.cleanupOrphanACMCertificates(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acmClientMock.addTagsCalled).To(BeTrue())
+			Expect(acmClientMock.deleteCalled).To(BeFalse())
+			Expect(report.Scanned).To(BeEquivalentTo(1))
+			Expect(report.Owned).To(BeEquivalentTo(1))
+			Expect(report.Orphaned).To(BeEquivalentTo(1))
+			Expect(report.Deleted).To(BeEquivalentTo(0))
+		})
+
+		It("Should keep an orphan during its grace period", func() {
+			acmClientMock.orphanedSince = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+
+			_, err := job.cleanupOrphanACMCertificates(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acmClientMock.addTagsCalled).To(BeFalse())
+			Expect(acmClientMock.deleteCalled).To(BeFalse())
+		})
+
+		It("Should delete an orphan once the grace period has elapsed", func() {
+			acmClientMock.orphanedSince = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+
+			report, err := job.cleanupOrphanACMCertificates(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acmClientMock.deleteCalled).To(BeTrue())
+			Expect(report.Deleted).To(BeEquivalentTo(1))
+		})
+
+		It("Should delete an orphan right away without grace period", func() {
+			job.GracePeriod = 0
+
+			_, err := job.cleanupOrphanACMCertificates(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acmClientMock.deleteCalled).To(BeTrue())
+		})
+
+		It("Should not change anything in dry-run mode", func() {
+			job.DryRun = true
+			acmClientMock.orphanedSince = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+
+			report, err := job.cleanupOrphanACMCertificates(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acmClientMock.addTagsCalled).To(BeFalse())
+			Expect(acmClientMock.deleteCalled).To(BeFalse())
+			Expect(report.Orphaned).To(BeEquivalentTo(1))
+			Expect(report.Deleted).To(BeEquivalentTo(0))
+		})
+
+		It("Should remove the orphaned tag when the certificate is restored", func() {
+			acmClientMock.orphanedSince = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+			job = newJob(&certificatev1beta1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-arn", Namespace: "default"},
+			})
+
+			report, err := job.cleanupOrphanACMCertificates(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acmClientMock.removeTagsCalled).To(BeTrue())
+			Expect(acmClientMock.deleteCalled).To(BeFalse())
+			Expect(report.Orphaned).To(BeEquivalentTo(0))
+		})
+	})
+
+	Context("clean certificates of other accounts and regions", func() {
+		It("Should tag the certificates replaced in another role or region", func() {
+			defer func(roles []string) { AllowedIssuerRoleARNs = roles }(AllowedIssuerRoleARNs)
+			AllowedIssuerRoleARNs = []string{"arn:aws:iam::123456789012:role/acm"}
+			issuerMock := &acmClientCleanupMock{arns: []string{"old-arn"}}
+			job = newJob(
+				&certificatev1beta1.ACMIssuer{
+					ObjectMeta: metav1.ObjectMeta{Name: "production", Namespace: "default"},
+					Spec:       certificatev1beta1.ACMIssuerSpec{Region: "us-east-1", RoleARN: "arn:aws:iam::123456789012:role/acm"},
+				},
+				&certificatev1beta1.Certificate{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-arn", Namespace: "default"},
+					Status: certificatev1beta1.CertificateStatus{
+						CertificateArn: "test-arn",
+						Status:         certificatev1beta1.CertificateStatusIssued,
+					},
+				},
+			)
+			job.Clients = &cleanupClientFactoryMock{acm: map[acmLocation]*acmClientCleanupMock{
+				{region: "us-east-1", role: external_api_clients.Role{ARN: "arn:aws:iam::123456789012:role/acm", ExternalID: "default"}}: issuerMock,
+			}}
+
+			report, err := job.cleanupOrphanACMCertificates(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Scanned).To(BeEquivalentTo(2))
+			Expect(report.Orphaned).To(BeEquivalentTo(1))
+			Expect(acmClientMock.addTagsCalled).To(BeFalse())
+			Expect(issuerMock.addTagsCalled).To(BeTrue())
+		})
+	})
 
-		cleanupOrphanACMCertificates()
-		Expect(acmClientMock.deleteCalled).To(BeTrue())
-		Expect(certificateClientMock.getCalled).To(BeTrue())
+	Context("report", func() {
+		It("Should save the run report in the CleanupReport status", func() {
+			job.GracePeriod = 0
+			job.run(context.Background())
+
+			report := &certificatev1beta1.CleanupReport{}
+			Expect(job.Get(context.Background(), types.NamespacedName{Name: ACMManagerOwnerName}, report)).To(Succeed())
+			Expect(report.Status.Scanned).To(BeEquivalentTo(1))
+			Expect(report.Status.Deleted).To(BeEquivalentTo(1))
+			Expect(report.Status.LastRunTime).NotTo(BeNil())
+		})
 	})
 })
@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+// ACMErrorClass tells how the controller reacts to an ACM API error.
+type ACMErrorClass string
+
+const (
+	// ACMErrorRetryable errors are transient and retried with the default backoff
+	ACMErrorRetryable ACMErrorClass = "Retryable"
+	// ACMErrorThrottling errors are retried with an increasing delay
+	ACMErrorThrottling ACMErrorClass = "Throttling"
+	// ACMErrorPermission errors need a fix of the credentials or IAM policy and are retried slowly
+	ACMErrorPermission ACMErrorClass = "Permission"
+	// ACMErrorTerminal errors will fail again until the Certificate is changed and are not retried
+	ACMErrorTerminal ACMErrorClass = "Terminal"
+)
+
+const (
+	// permissionErrorRetryInterval is the delay before retrying after a permission error
+	permissionErrorRetryInterval = time.Minute * 5
+
+	throttlingBaseDelay = time.Second * 10
+	throttlingMaxDelay  = time.Minute * 10
+)
+
+var acmTerminalErrorCodes = map[string]struct{}{
+	"InvalidDomainValidationOptionsException": {},
+	"InvalidParameterException":               {},
+	"InvalidArnException":                     {},
+	"InvalidArgsException":                    {},
+	"InvalidTagException":                     {},
+	"TooManyTagsException":                    {},
+	"TagPolicyException":                      {},
+	"ValidationException":                     {},
+	// ACM uses it for the certificate quotas of the account, not for throttling
+	"LimitExceededException": {},
+}
+
+var acmPermissionErrorCodes = map[string]struct{}{
+	"AccessDenied":                  {},
+	"AccessDeniedException":         {},
+	"UnauthorizedOperation":         {},
+	"UnrecognizedClientException":   {},
+	"InvalidClientTokenId":          {},
+	"InvalidSignatureException":     {},
+	"ExpiredToken":                  {},
+	"ExpiredTokenException":         {},
+	"SignatureDoesNotMatch":         {},
+	"MissingAuthenticationToken":    {},
+	"NotAuthorized":                 {},
+	"AuthFailure":                   {},
+	"IncompleteSignature":           {},
+	"OptInRequired":                 {},
+	"SubscriptionRequiredException": {},
+	"RequestExpired":                {},
+}
+
+// ClassifyACMError returns the class of an error returned by the ACM API.
+// Errors that are not ACM API errors, like network failures, are retryable.
+func ClassifyACMError(err error) ACMErrorClass {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return ACMErrorRetryable
+	}
+
+	code := apiErr.ErrorCode()
+	if _, ok := acmTerminalErrorCodes[code]; ok {
+		return ACMErrorTerminal
+	}
+	if _, ok := acmPermissionErrorCodes[code]; ok {
+		return ACMErrorPermission
+	}
+	if _, ok := retry.DefaultThrottleErrorCodes[code]; ok {
+		return ACMErrorThrottling
+	}
+
+	return ACMErrorRetryable
+}
+
+// ConditionReason returns the Ready condition reason reported for the class.
+func (c ACMErrorClass) ConditionReason() string {
+	switch c {
+	case ACMErrorTerminal:
+		return certificatev1beta1.CertificateReasonInvalidRequest
+	case ACMErrorPermission:
+		return certificatev1beta1.CertificateReasonPermissionDenied
+	case ACMErrorThrottling:
+		return certificatev1beta1.CertificateReasonThrottled
+	default:
+		return certificatev1beta1.CertificateReasonRequestFailed
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+var _ = Describe("ACM error classification", func() {
+	apiError := func(code string) error {
+		// errors are wrapped the same way the reconciler does
+		return fmt.Errorf("unable to request certificate: %w", &smithy.GenericAPIError{Code: code, Message: "test"})
+	}
+
+	table.DescribeTable("Should classify ACM API errors",
+		func(err error, class ACMErrorClass, reason string) {
+			Expect(ClassifyACMError(err)).To(Equal(class))
+			Expect(ClassifyACMError(err).ConditionReason()).To(Equal(reason))
+		},
+		table.Entry("invalid domain", apiError("InvalidDomainValidationOptionsException"), ACMErrorTerminal, certificatev1beta1.CertificateReasonInvalidRequest),
+		table.Entry("certificate quota", apiError("LimitExceededException"), ACMErrorTerminal, certificatev1beta1.CertificateReasonInvalidRequest),
+		table.Entry("access denied", apiError("AccessDeniedException"), ACMErrorPermission, certificatev1beta1.CertificateReasonPermissionDenied),
+		table.Entry("expired token", apiError("ExpiredTokenException"), ACMErrorPermission, certificatev1beta1.CertificateReasonPermissionDenied),
+		table.Entry("throttling", apiError("ThrottlingException"), ACMErrorThrottling, certificatev1beta1.CertificateReasonThrottled),
+		table.Entry("unknown API error", apiError("InternalFailure"), ACMErrorRetryable, certificatev1beta1.CertificateReasonRequestFailed),
+		table.Entry("network error", errors.New("connection reset by peer"), ACMErrorRetryable, certificatev1beta1.CertificateReasonRequestFailed),
+	)
+})
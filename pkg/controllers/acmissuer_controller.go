@@ -0,0 +1,239 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"vdesjardins/acm-manager/pkg/controllers/external_api_clients"
+
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+const (
+	// issuerProbeInterval is how often the credentials of a ready issuer are verified
+	issuerProbeInterval = time.Minute * 10
+	// issuerRetryInterval is the delay before verifying failing credentials again
+	issuerRetryInterval = time.Minute
+)
+
+// AllowedIssuerRoleARNs are the IAM roles the namespaced ACMIssuers can assume.
+// An entry ending with * allows the roles starting with it. ClusterACMIssuers
+// can assume any role.
+var AllowedIssuerRoleARNs []string
+
+// ACMIssuerReconciler verifies the credentials of ACMIssuers or ClusterACMIssuers
+// by calling STS with them.
+type ACMIssuerReconciler struct {
+	client.Client
+	Scheme  *runtime.Scheme
+	Clients external_api_clients.ClientFactory
+
+	// Kind of the reconciled issuers, ACMIssuer or ClusterACMIssuer
+	Kind string
+}
+
+//+kubebuilder:rbac:groups=acm-manager.io,resources=acmissuers;clusteracmissuers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=acm-manager.io,resources=acmissuers/status;clusteracmissuers/status,verbs=get;update;patch
+
+// Reconcile probes the credentials of an issuer and reports them in its Ready condition.
+func (r *ACMIssuerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	issuer := newIssuer(r.Kind)
+	if err := r.Get(ctx, req.NamespacedName, issuer); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	spec := issuer.GetSpec()
+	status := issuer.GetStatus()
+	now := metav1.Now()
+	status.LastProbeTime = &now
+
+	result := ctrl.Result{RequeueAfter: issuerProbeInterval}
+	role, err := issuerRole(issuer)
+	if err != nil {
+		log.Info("issuer role refused", "reason", err.Error())
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               certificatev1beta1.IssuerConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             certificatev1beta1.IssuerReasonRoleNotAllowed,
+			Message:            err.Error(),
+			ObservedGeneration: issuer.GetGeneration(),
+		})
+		status.Account = ""
+		// the allowed roles only change with the controller flags
+		result = ctrl.Result{}
+	} else if output, err := r.Clients.STS(spec.Region, role).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{}); err != nil {
+		log.Error(err, "issuer credentials probe failed")
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               certificatev1beta1.IssuerConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             certificatev1beta1.IssuerReasonCredentialsError,
+			Message:            err.Error(),
+			ObservedGeneration: issuer.GetGeneration(),
+		})
+		result.RequeueAfter = issuerRetryInterval
+	} else {
+		status.Account = *output.Account
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               certificatev1beta1.IssuerConditionReady,
+			Status:             metav1.ConditionTrue,
+			Reason:             certificatev1beta1.IssuerReasonVerified,
+			Message:            fmt.Sprintf("credentials verified for account %s", status.Account),
+			ObservedGeneration: issuer.GetGeneration(),
+		})
+	}
+
+	if err := r.Status().Update(ctx, issuer); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to update issuer status: %w", err)
+	}
+
+	return result, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ACMIssuerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// the status is updated on every probe, the credentials are only probed
+		// again on a spec change or once the probe interval elapsed
+		For(newIssuer(r.Kind), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named(strings.ToLower(r.Kind)).
+		Complete(r)
+}
+
+// newIssuer returns an empty issuer of the kind.
+func newIssuer(kind string) interface {
+	client.Object
+	certificatev1beta1.GenericIssuer
+} {
+	if kind == certificatev1beta1.ClusterIssuerKind {
+		return &certificatev1beta1.ClusterACMIssuer{}
+	}
+	return &certificatev1beta1.ACMIssuer{}
+}
+
+// issuerRole returns the IAM role assumed with an issuer. The role of an
+// ACMIssuer must be allowed by AllowedIssuerRoleARNs and is assumed with the
+// namespace of the issuer as external ID, so the trust policy of the role can
+// restrict the namespaces using it.
+func issuerRole(issuer certificatev1beta1.GenericIssuer) (external_api_clients.Role, error) {
+	role := external_api_clients.Role{ARN: issuer.GetSpec().RoleARN}
+	if role.ARN == "" || issuer.GetNamespace() == "" {
+		return role, nil
+	}
+
+	for _, allowed := range AllowedIssuerRoleARNs {
+		prefix, wildcard := strings.CutSuffix(allowed, "*")
+		if allowed == role.ARN || wildcard && strings.HasPrefix(role.ARN, prefix) {
+			role.ExternalID = issuer.GetNamespace()
+			return role, nil
+		}
+	}
+	return role, fmt.Errorf("role %s is not allowed for %s issuers", role.ARN, certificatev1beta1.IssuerKind)
+}
+
+// IssuerNotReadyError is returned when the issuer of a Certificate is missing,
+// its credentials were not verified or its role is not allowed.
+type IssuerNotReadyError struct {
+	Kind string
+	Name string
+	// NotFound is true when the issuer does not exist
+	NotFound bool
+	// Reason is why the issuer can not be used, when known
+	Reason string
+}
+
+func (e *IssuerNotReadyError) Error() string {
+	if e.NotFound {
+		return fmt.Sprintf("%s %s not found", e.Kind, e.Name)
+	}
+	if e.Reason != "" {
+		return fmt.Sprintf("%s %s is not ready: %s", e.Kind, e.Name, e.Reason)
+	}
+	return fmt.Sprintf("%s %s is not ready", e.Kind, e.Name)
+}
+
+// getIssuer returns the issuer referenced by a Certificate, or nil when the
+// Certificate does not reference one.
+func (r *CertificateReconciler) getIssuer(ctx context.Context, cert *certificatev1beta1.Certificate) (certificatev1beta1.GenericIssuer, error) {
+	return getIssuer(ctx, r.Client, cert.Namespace, cert.Spec.IssuerRef)
+}
+
+// getIssuer returns the issuer referenced from a namespace, or an
+// IssuerNotReadyError if it is missing or not ready.
+func getIssuer(ctx context.Context, reader client.Reader, namespace string, ref *certificatev1beta1.IssuerReference) (certificatev1beta1.GenericIssuer, error) {
+	if ref == nil {
+		return nil, nil
+	}
+
+	kind := ref.Kind
+	if kind == "" {
+		kind = certificatev1beta1.IssuerKind
+	}
+	key := types.NamespacedName{Name: ref.Name}
+	if kind == certificatev1beta1.IssuerKind {
+		key.Namespace = namespace
+	}
+
+	issuer := newIssuer(kind)
+	if err := reader.Get(ctx, key, issuer); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return nil, &IssuerNotReadyError{Kind: kind, Name: ref.Name, NotFound: true}
+		}
+		return nil, fmt.Errorf("unable to fetch %s %s: %w", kind, ref.Name, err)
+	}
+	if !issuer.GetStatus().IsReady() {
+		return issuer, &IssuerNotReadyError{Kind: kind, Name: ref.Name}
+	}
+	// the role is checked again in case it changed since the issuer was verified
+	if _, err := issuerRole(issuer); err != nil {
+		return issuer, &IssuerNotReadyError{Kind: kind, Name: ref.Name, Reason: err.Error()}
+	}
+
+	return issuer, nil
+}
+
+// acmClientFor returns the ACM client of the issuer of a Certificate.
+func (r *CertificateReconciler) acmClientFor(ctx context.Context, cert *certificatev1beta1.Certificate) (external_api_clients.AcmAWSAPI, error) {
+	issuer, err := r.getIssuer(ctx, cert)
+	if err != nil {
+		return nil, err
+	}
+	if issuer == nil {
+		return r.ACMClient, nil
+	}
+
+	role, err := issuerRole(issuer)
+	if err != nil {
+		return nil, err
+	}
+	return r.Clients.ACM(issuer.GetSpec().Region, role), nil
+}
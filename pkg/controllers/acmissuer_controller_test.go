@@ -0,0 +1,211 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"vdesjardins/acm-manager/pkg/controllers/external_api_clients"
+
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+type stsClientFailingMock struct{}
+
+func (s *stsClientFailingMock) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	return nil, errors.New("AccessDenied: not authorized to perform sts:AssumeRole")
+}
+
+// clientFactoryMock records the region and role of the requested clients.
+type clientFactoryMock struct {
+	sts    external_api_clients.StsAWSAPI
+	pca    external_api_clients.PcaAWSAPI
+	region string
+	role   external_api_clients.Role
+}
+
+func (f *clientFactoryMock) ACM(region string, role external_api_clients.Role) external_api_clients.AcmAWSAPI {
+	f.region, f.role = region, role
+	return &acmClientMock{}
+}
+
+func (f *clientFactoryMock) STS(region string, role external_api_clients.Role) external_api_clients.StsAWSAPI {
+	f.region, f.role = region, role
+	return f.sts
+}
+
+func (f *clientFactoryMock) PCA(region string, role external_api_clients.Role) external_api_clients.PcaAWSAPI {
+	f.region, f.role = region, role
+	return f.pca
+}
+
+var _ = Describe("ACM issuers", func() {
+	readyCondition := metav1.Condition{
+		Type:   certificatev1beta1.IssuerConditionReady,
+		Status: metav1.ConditionTrue,
+		Reason: certificatev1beta1.IssuerReasonVerified,
+	}
+
+	It("Should probe the credentials of an issuer", func() {
+		ctx := context.Background()
+		issuer := &certificatev1beta1.ClusterACMIssuer{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod"},
+			Spec: certificatev1beta1.ACMIssuerSpec{
+				Region:  "us-east-1",
+				RoleARN: "arn:aws:iam::123456789012:role/acm-manager",
+			},
+		}
+		clients := &clientFactoryMock{sts: &stsClientMock{}}
+		r := &ACMIssuerReconciler{
+			Client:  newFakeClient(issuer),
+			Clients: clients,
+			Kind:    certificatev1beta1.ClusterIssuerKind,
+		}
+
+		By("setting the account of verified credentials")
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "prod"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(issuerProbeInterval))
+		Expect(clients.region).To(Equal("us-east-1"))
+		Expect(clients.role).To(Equal(external_api_clients.Role{ARN: "arn:aws:iam::123456789012:role/acm-manager"}))
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: "prod"}, issuer)).To(Succeed())
+		Expect(issuer.Status.Account).To(Equal("123456789012"))
+		Expect(issuer.Status.IsReady()).To(BeTrue())
+
+		By("reporting credentials that can not be used")
+		clients.sts = &stsClientFailingMock{}
+		result, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "prod"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(issuerRetryInterval))
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: "prod"}, issuer)).To(Succeed())
+		Expect(issuer.Status.IsReady()).To(BeFalse())
+		Expect(issuer.Status.Conditions[0].Reason).To(Equal(certificatev1beta1.IssuerReasonCredentialsError))
+	})
+
+	It("Should only assume the allowed roles with namespaced issuers", func() {
+		ctx := context.Background()
+		defer func(roles []string) { AllowedIssuerRoleARNs = roles }(AllowedIssuerRoleARNs)
+		AllowedIssuerRoleARNs = []string{"arn:aws:iam::123456789012:role/team-*"}
+
+		issuer := &certificatev1beta1.ACMIssuer{
+			ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "team-a"},
+			Spec:       certificatev1beta1.ACMIssuerSpec{RoleARN: "arn:aws:iam::123456789012:role/platform"},
+		}
+		clients := &clientFactoryMock{sts: &stsClientMock{}}
+		r := &ACMIssuerReconciler{
+			Client:  newFakeClient(issuer),
+			Clients: clients,
+			Kind:    certificatev1beta1.IssuerKind,
+		}
+		key := client.ObjectKeyFromObject(issuer)
+
+		By("refusing a role that is not allowed without assuming it")
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clients.role).To(BeZero())
+		Expect(r.Get(ctx, key, issuer)).To(Succeed())
+		Expect(issuer.Status.IsReady()).To(BeFalse())
+		Expect(issuer.Status.Conditions[0].Reason).To(Equal(certificatev1beta1.IssuerReasonRoleNotAllowed))
+
+		By("assuming an allowed role with the namespace as external ID")
+		issuer.Spec.RoleARN = "arn:aws:iam::123456789012:role/team-a"
+		Expect(r.Update(ctx, issuer)).To(Succeed())
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clients.role).To(Equal(external_api_clients.Role{ARN: "arn:aws:iam::123456789012:role/team-a", ExternalID: "team-a"}))
+		Expect(r.Get(ctx, key, issuer)).To(Succeed())
+		Expect(issuer.Status.IsReady()).To(BeTrue())
+
+		By("refusing a verified issuer whose role is no longer allowed")
+		issuer.Spec.RoleARN = "arn:aws:iam::123456789012:role/platform"
+		Expect(r.Update(ctx, issuer)).To(Succeed())
+		var issuerErr *IssuerNotReadyError
+		_, err = getIssuer(ctx, r.Client, "team-a", &certificatev1beta1.IssuerReference{Name: "team"})
+		Expect(errors.As(err, &issuerErr)).To(BeTrue())
+		Expect(issuerErr.Reason).NotTo(BeEmpty())
+	})
+
+	It("Should only use ready issuers of the namespace of the Certificate", func() {
+		ctx := context.Background()
+		ready := &certificatev1beta1.ACMIssuer{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "default"},
+			Spec:       certificatev1beta1.ACMIssuerSpec{Region: "eu-west-1"},
+			Status: certificatev1beta1.ACMIssuerStatus{
+				Account:    "123456789012",
+				Conditions: []metav1.Condition{readyCondition},
+			},
+		}
+		pending := &certificatev1beta1.ACMIssuer{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"},
+		}
+		clients := &clientFactoryMock{}
+		r := &CertificateReconciler{Client: newFakeClient(ready, pending), ACMClient: &acmClientMock{}, Clients: clients}
+
+		cert := newCert("test-cert", "default")
+		_, err := r.acmClientFor(ctx, cert)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clients.region).To(BeEmpty())
+
+		var issuerErr *IssuerNotReadyError
+		cert.Spec.IssuerRef = &certificatev1beta1.IssuerReference{Name: "pending"}
+		_, err = r.acmClientFor(ctx, cert)
+		Expect(errors.As(err, &issuerErr)).To(BeTrue())
+		Expect(issuerErr.NotFound).To(BeFalse())
+
+		cert.Spec.IssuerRef = &certificatev1beta1.IssuerReference{Name: "ready", Kind: certificatev1beta1.ClusterIssuerKind}
+		_, err = r.acmClientFor(ctx, cert)
+		Expect(errors.As(err, &issuerErr)).To(BeTrue())
+		Expect(issuerErr.NotFound).To(BeTrue())
+
+		cert.Spec.IssuerRef = &certificatev1beta1.IssuerReference{Name: "ready"}
+		_, err = r.acmClientFor(ctx, cert)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clients.region).To(Equal("eu-west-1"))
+	})
+
+	It("Should request the certificate with the private CA and tags of the issuer", func() {
+		issuer := &certificatev1beta1.ACMIssuer{
+			Spec: certificatev1beta1.ACMIssuerSpec{
+				PrivateCAARN: "arn:aws:acm-pca:us-east-1:123456789012:certificate-authority/test",
+				Tags:         map[string]string{"team": "platform", "env": "prod"},
+			},
+		}
+		cert := newCert("test-cert", "default")
+		cert.Spec.Tags = map[string]string{"team": "web"}
+
+		input := newRequestCertificateInput(cert, issuer)
+		Expect(*input.CertificateAuthorityArn).To(Equal(issuer.Spec.PrivateCAARN))
+		Expect(input.ValidationMethod).To(BeEmpty())
+
+		tags := map[string]string{}
+		for _, t := range input.Tags {
+			tags[*t.Key] = *t.Value
+		}
+		Expect(tags).To(HaveKeyWithValue("team", "web"))
+		Expect(tags).To(HaveKeyWithValue("env", "prod"))
+	})
+})
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+// CertificateClassNotFoundError is returned when a Certificate names a
+// CertificateClass that does not exist.
+type CertificateClassNotFoundError struct {
+	Name string
+}
+
+func (e *CertificateClassNotFoundError) Error() string {
+	return fmt.Sprintf("CertificateClass %s not found", e.Name)
+}
+
+//+kubebuilder:rbac:groups=acm-manager.io,resources=certificateclasses,verbs=get;list;watch
+
+// GetCertificateClass returns the CertificateClass of the given name, or the
+// default class when name is empty. When several classes are marked as default
+// the most recently created one is used. It returns nil when name is empty and
+// there is no default class.
+func GetCertificateClass(ctx context.Context, c client.Reader, name string) (*certificatev1beta1.CertificateClass, error) {
+	if name != "" {
+		class := &certificatev1beta1.CertificateClass{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, class); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, &CertificateClassNotFoundError{Name: name}
+			}
+			return nil, fmt.Errorf("unable to fetch CertificateClass %s: %w", name, err)
+		}
+		return class, nil
+	}
+
+	classes := &certificatev1beta1.CertificateClassList{}
+	if err := c.List(ctx, classes); err != nil {
+		return nil, fmt.Errorf("unable to list CertificateClasses: %w", err)
+	}
+
+	var found *certificatev1beta1.CertificateClass
+	for i := range classes.Items {
+		class := &classes.Items[i]
+		if !class.IsDefault() {
+			continue
+		}
+		if found == nil || found.CreationTimestamp.Before(&class.CreationTimestamp) ||
+			found.CreationTimestamp.Equal(&class.CreationTimestamp) && class.Name < found.Name {
+			found = class
+		}
+	}
+
+	return found, nil
+}
+
+// applyCertificateClass fills the spec fields the Certificate does not set with
+// the ones of its class. Only the in-memory Certificate is changed.
+func (r *CertificateReconciler) applyCertificateClass(ctx context.Context, cert *certificatev1beta1.Certificate) error {
+	class, err := GetCertificateClass(ctx, r, cert.Spec.CertificateClassName)
+	if err != nil || class == nil {
+		return err
+	}
+	class.Spec.ApplyTo(&cert.Spec)
+	return nil
+}
+
+// certificatesForClass maps a CertificateClass to the Certificates using it,
+// explicitly or as the default class.
+func (r *CertificateReconciler) certificatesForClass(ctx context.Context, obj client.Object) []reconcile.Request {
+	class, ok := obj.(*certificatev1beta1.CertificateClass)
+	if !ok {
+		return nil
+	}
+
+	certs := &certificatev1beta1.CertificateList{}
+	if err := r.List(ctx, certs); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, cert := range certs.Items {
+		name := cert.Spec.CertificateClassName
+		if name == class.Name || name == "" && class.IsDefault() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cert)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+var _ = Describe("Certificate classes", func() {
+	newClass := func(name string, isDefault bool, created time.Time) *certificatev1beta1.CertificateClass {
+		class := &certificatev1beta1.CertificateClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Spec: certificatev1beta1.CertificateClassSpec{
+				Options: &certificatev1beta1.CertificateOptions{
+					KeyAlgorithm:                   certificatev1beta1.CertificateKeyAlgorithmECSecp384r1,
+					CertificateTransparencyLogging: certificatev1beta1.CertificateTransparencyLoggingDisabled,
+				},
+				Tags:           map[string]string{"team": "platform", "env": "prod"},
+				DeletionPolicy: certificatev1beta1.CertificateDeletionPolicyRetain,
+			},
+		}
+		if isDefault {
+			class.Annotations = map[string]string{certificatev1beta1.DefaultCertificateClassAnnotation: "true"}
+		}
+		return class
+	}
+
+	It("Should resolve the named class or the newest default class", func() {
+		ctx := context.Background()
+		now := time.Now()
+		c := newFakeClient(
+			newClass("old-default", true, now.Add(-time.Hour)),
+			newClass("default", true, now),
+			newClass("other", false, now),
+		)
+
+		class, err := GetCertificateClass(ctx, c, "other")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(class.Name).To(Equal("other"))
+
+		class, err = GetCertificateClass(ctx, c, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(class.Name).To(Equal("default"))
+
+		_, err = GetCertificateClass(ctx, c, "missing")
+		var classErr *CertificateClassNotFoundError
+		Expect(errors.As(err, &classErr)).To(BeTrue())
+
+		class, err = GetCertificateClass(ctx, newFakeClient(), "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(class).To(BeNil())
+	})
+
+	It("Should only fill the fields the Certificate does not set", func() {
+		r := &CertificateReconciler{Client: newFakeClient(newClass("profile", false, time.Now()))}
+		cert := newCert("test-cert", "default")
+		cert.Spec.CertificateClassName = "profile"
+		cert.Spec.Options = &certificatev1beta1.CertificateOptions{KeyAlgorithm: certificatev1beta1.CertificateKeyAlgorithmRSA2048}
+		cert.Spec.Tags = map[string]string{"team": "web"}
+
+		Expect(r.applyCertificateClass(context.Background(), cert)).To(Succeed())
+		Expect(cert.Spec.KeyAlgorithm()).To(Equal(certificatev1beta1.CertificateKeyAlgorithmRSA2048))
+		Expect(cert.Spec.Options.CertificateTransparencyLogging).To(Equal(certificatev1beta1.CertificateTransparencyLoggingDisabled))
+		Expect(cert.Spec.Tags).To(Equal(map[string]string{"team": "web", "env": "prod"}))
+		Expect(cert.Spec.DeletionPolicy).To(Equal(certificatev1beta1.CertificateDeletionPolicyRetain))
+	})
+})
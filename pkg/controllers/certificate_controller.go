@@ -20,10 +20,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 	"vdesjardins/acm-manager/pkg/controllers/external_api_clients"
 
-	certac "vdesjardins/acm-manager/pkg/client/applyconfiguration/acmmanager/v1alpha1"
+	certac "vdesjardins/acm-manager/pkg/client/applyconfiguration/acmmanager/v1beta1"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -32,32 +36,40 @@ import (
 	"github.com/aws/smithy-go"
 	multierror "github.com/hashicorp/go-multierror"
 	core "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	dnsendpoint "sigs.k8s.io/external-dns/apis/v1alpha1"
 	endpoint "sigs.k8s.io/external-dns/endpoint"
 
-	certificatev1alpha1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1alpha1"
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
 	certificateclient "vdesjardins/acm-manager/pkg/client/versioned"
+	"vdesjardins/acm-manager/pkg/policy"
 )
 
 var (
-	ACMManagerOwnerName           = "acm-manager"
-	ACMManagerFieldManager        = "acm-manager"
-	ACMCertificateCleanupInterval = 6 * time.Hour
+	ACMManagerOwnerName    = "acm-manager"
+	ACMManagerFieldManager = "acm-manager"
 )
 
 const (
 	TagCertificateOwner     = "acm-manager/owner"
 	TagCertificateNamespace = "acm-manager/certificate-namespace"
 	TagCertificateName      = "acm-manager/certificate-name"
+	TagCertificateSpecHash  = "acm-manager/spec-hash"
+
+	// TagReservedPrefix is the prefix of the tags managed by the controller
+	TagReservedPrefix = "acm-manager/"
 )
 
 const (
@@ -67,6 +79,9 @@ const (
 	CertificateEventUpdateError    = "UpdateError"
 	CertificateEventCleanupError   = "CleanupError"
 	CertificateEventCleanupSuccess = "SuccessfulCleanup"
+	CertificateEventRequestBudget  = "RequestBudgetExceeded"
+	CertificateEventIssuerNotReady = "IssuerNotReady"
+	CertificateEventPolicyDenied   = "PolicyDenied"
 )
 
 // CertificateReconciler reconciles a Certificate object
@@ -74,8 +89,28 @@ type CertificateReconciler struct {
 	client.Client
 	certClient certificateclient.Interface
 	Scheme     *runtime.Scheme
-	svc        external_api_clients.AcmAWSAPI
+	ACMClient  external_api_clients.AcmAWSAPI
 	recorder   record.EventRecorder
+
+	// Clients returns the AWS clients of the issuers referenced by Certificates
+	Clients external_api_clients.ClientFactory
+
+	// Policy enforces the DomainPolicies of the namespaces. Disabled when nil.
+	Policy *policy.Checker
+
+	// RequestBudget guards the ACM certificate request quota. Disabled when nil.
+	RequestBudget *RequestBudget
+
+	// SpecSettleWindow is how long a spec change must stay unchanged before a
+	// new ACM certificate is requested for it. Disabled when zero.
+	SpecSettleWindow time.Duration
+
+	// VerifyInterval is how often the ACM certificate is compared with a spec
+	// whose hash did not change. The comparison is done on every reconcile when zero.
+	VerifyInterval time.Duration
+
+	throttlingOnce sync.Once
+	throttling     workqueue.TypedRateLimiter[types.NamespacedName]
 }
 
 //+kubebuilder:rbac:groups=acm-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
@@ -92,7 +127,7 @@ type CertificateReconciler struct {
 func (r *CertificateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
-	certificate := &certificatev1alpha1.Certificate{}
+	certificate := &certificatev1beta1.Certificate{}
 	if err := r.Get(ctx, req.NamespacedName, certificate); err != nil {
 		log.Error(err, "unable to fetch Certificate")
 		// we'll ignore not-found errors, since they can't be fixed by an immediate
@@ -118,11 +153,31 @@ func (r *CertificateReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	} else {
 		// The object is being deleted
 		if containsString(certificate.GetFinalizers(), finalizerName) {
-			// our finalizer is present, so lets handle any external dependency
-			if err := r.deleteACMCertificate(ctx, certificate); err != nil {
-				// if fail to delete the external dependency here, return with error
-				// so that it can be retried
-				return ctrl.Result{}, err
+			// our finalizer is present, so lets handle any external dependency.
+			// The deletion policy may come from the class, a missing class
+			// leaves the spec as is
+			effective := certificate.DeepCopy()
+			if err := r.applyCertificateClass(ctx, effective); err != nil {
+				log.Error(err, "unable to apply certificate class")
+			}
+			// the ACM certificate is released in the account and region it
+			// was requested in
+			if requestedLocationChanged(effective) {
+				effective.Spec.IssuerRef = effective.Status.RequestedIssuerRef
+				effective.Spec.Region = effective.Status.RequestedRegion
+			}
+			if err := r.releaseACMCertificate(ctx, effective); err != nil {
+				var issuerErr *IssuerNotReadyError
+				if !errors.As(err, &issuerErr) || !issuerErr.NotFound {
+					// if fail to delete the external dependency here, return with error
+					// so that it can be retried
+					return ctrl.Result{}, err
+				}
+				// the issuer is usually deleted along with its namespace, the
+				// cleanup job only finds the ACM certificate while another
+				// issuer uses the same role and region
+				log.Info("issuer not found, ACM certificate not released", "ARN", certificate.Status.CertificateArn)
+				r.recorder.Event(certificate, core.EventTypeWarning, CertificateEventIssuerNotReady, err.Error())
 			}
 
 			// remove our finalizer from the list and update it.
@@ -136,43 +191,115 @@ func (r *CertificateReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
+	// options of the class apply to the fields the Certificate does not set
+	if err := r.applyCertificateClass(ctx, certificate); err != nil {
+		var classErr *CertificateClassNotFoundError
+		if !errors.As(err, &classErr) {
+			return ctrl.Result{}, err
+		}
+		log.Info("waiting for certificate class", "reason", err.Error())
+		setCertificateCondition(certificate, metav1.ConditionFalse, certificatev1beta1.CertificateReasonClassNotFound, err.Error())
+		if err := r.updateWithStatus(ctx, certificate); err != nil {
+			log.Error(err, "unable to update status")
+		}
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	// the issuer must be ready before calling ACM with its credentials
+	if _, err := r.getIssuer(ctx, certificate); err != nil {
+		var issuerErr *IssuerNotReadyError
+		if !errors.As(err, &issuerErr) {
+			return ctrl.Result{}, err
+		}
+		log.Info("waiting for issuer", "reason", err.Error())
+		setCertificateCondition(certificate, metav1.ConditionFalse, certificatev1beta1.CertificateReasonIssuerNotReady, err.Error())
+		if err := r.updateWithStatus(ctx, certificate); err != nil {
+			log.Error(err, "unable to update status")
+		}
+		return ctrl.Result{RequeueAfter: issuerRetryInterval}, nil
+	}
+
+	// the domain policies of the namespace must allow the certificate
+	if r.Policy != nil {
+		domains := append([]string{certificate.Spec.CommonName}, certificate.Spec.SubjectAlternativeNames...)
+		if err := r.Policy.Check(ctx, certificate.Namespace, certificate.Name, domains); err != nil {
+			var deniedErr *policy.DeniedError
+			if !errors.As(err, &deniedErr) {
+				return ctrl.Result{}, err
+			}
+			log.Info("certificate denied by domain policy", "reason", err.Error())
+			r.recorder.Event(certificate, core.EventTypeWarning, CertificateEventPolicyDenied, err.Error())
+			setCertificateCondition(certificate, metav1.ConditionFalse, certificatev1beta1.CertificateReasonPolicyDenied, err.Error())
+			if err := r.updateWithStatus(ctx, certificate); err != nil {
+				log.Error(err, "unable to update status")
+			}
+			// reconciled again when the domain policies change
+			return ctrl.Result{}, nil
+		}
+	}
+
 	// create cert request if does not exist
 	certificateCreated := false
+	verified := false
 	if certificate.Status.CertificateArn == "" {
 		if err := r.requestACMCertificate(ctx, certificate); err != nil {
-			log.Error(err, "unable to request certificate")
-			r.recorder.Event(certificate, core.EventTypeWarning, CertificateEventRequestError, err.Error())
-			certificate.Status.Status = certificatev1alpha1.CertificateStatusError
-			if err := r.updateWithStatus(ctx, certificate); err != nil {
-				log.Error(err, "unable to update status")
-			}
-			return ctrl.Result{}, err
+			return r.handleRequestError(ctx, certificate, err)
 		}
 		certificateCreated = true
 	} else {
 		// if exists need to check if it has changed
-		equals, err := r.compareACMCertificate(ctx, certificate)
-		if err != nil {
-			log.Error(err, "unable to update ACM certificate")
-			r.recorder.Event(certificate, core.EventTypeWarning, CertificateEventCompareError, err.Error())
-			return ctrl.Result{}, err
+		equals, found := true, true
+		if requestedLocationChanged(certificate) {
+			// the ACM certificate is in the account or region of the previous
+			// issuer, describing it with the client of the spec would fail
+			log.Info("certificate issuer or region changed", "ARN", certificate.Status.CertificateArn)
+			equals = false
+		} else if r.needsACMComparison(certificate) {
+			var err error
+			equals, found, err = r.compareACMCertificate(ctx, certificate)
+			if err != nil {
+				return r.handleACMError(ctx, certificate, CertificateEventCompareError, err)
+			}
+			verified = true
+			if equals {
+				// the certificate still matches a changed spec, its tag is
+				// updated instead of requesting it again. also stamps
+				// certificates requested before the hash was recorded
+				if hash := certificateSpecHash(&certificate.Spec); certificate.Status.SpecHash != hash {
+					if err := r.updateSpecHashTag(ctx, certificate); err != nil {
+						return r.handleACMError(ctx, certificate, CertificateEventUpdateError, err)
+					}
+					certificate.Status.SpecHash = hash
+				}
+				certificate.Status.LastVerifiedTime = &metav1.Time{Time: time.Now()}
+			}
 		}
 		if !equals {
+			// wait for the spec to settle to avoid requesting a certificate
+			// for every intermediate change. a deleted certificate is
+			// replaced right away
+			if !found {
+				log.Info("certificate not found in ACM", "ARN", certificate.Status.CertificateArn)
+			} else if wait := r.specSettleDelay(certificate); wait > 0 {
+				log.Info("waiting for certificate spec to settle", "specHash", certificate.Status.PendingSpecHash, "wait", wait)
+				if err := r.updateWithStatus(ctx, certificate); err != nil {
+					log.Error(err, "unable to update certificate resource status")
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{RequeueAfter: wait}, nil
+			}
+
 			// create a new cert request
 			// first, clear status
 			if err := r.requestACMCertificate(ctx, certificate); err != nil {
-				log.Error(err, "unable to request certificate")
-				r.recorder.Event(certificate, core.EventTypeWarning, CertificateEventRequestError, err.Error())
-				certificate.Status.Status = certificatev1alpha1.CertificateStatusError
-				if err := r.updateWithStatus(ctx, certificate); err != nil {
-					log.Error(err, "unable to update status")
-				}
-				return ctrl.Result{}, err
+				return r.handleRequestError(ctx, certificate, err)
 			}
 			// clear status
-			certificate.Status.ResourceRecords = []certificatev1alpha1.ResourceRecord{}
+			certificate.Status.ResourceRecords = []certificatev1beta1.ResourceRecord{}
 			certificateCreated = true
 		}
+		certificate.Status.PendingSpecHash = ""
+		certificate.Status.PendingSpecSince = nil
 	}
 
 	// save status state
@@ -185,15 +312,25 @@ func (r *CertificateReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
-	// update certificate information from ACM if available
-	requeue, err := r.updateCertificateInfo(ctx, certificate)
-	if err != nil {
-		log.Error(err, "unable to update certificate info")
-		r.recorder.Event(certificate, core.EventTypeWarning, CertificateEventUpdateError, err.Error())
-		if certificateCreated {
-			r.deleteACMCertificate(ctx, certificate)
+	// update certificate information from ACM if available. an issued
+	// certificate keeps the information of its last verification
+	requeue := false
+	if certificateCreated || verified || !certificateInfoUpToDate(certificate) {
+		var err error
+		requeue, err = r.updateCertificateInfo(ctx, certificate)
+		if err != nil {
+			if certificateCreated {
+				r.deleteACMCertificate(ctx, certificate)
+			}
+			return r.handleACMError(ctx, certificate, CertificateEventUpdateError, err)
 		}
-		return ctrl.Result{}, err
+	}
+	r.throttlingBackoff().Forget(req.NamespacedName)
+	if certificate.Status.Status == certificatev1beta1.CertificateStatusIssued {
+		setCertificateCondition(certificate, metav1.ConditionTrue, certificatev1beta1.CertificateReasonIssued, "ACM certificate is issued")
+	} else {
+		setCertificateCondition(certificate, metav1.ConditionFalse, certificatev1beta1.CertificateReasonPending,
+			fmt.Sprintf("ACM certificate status is %s", certificate.Status.Status))
 	}
 
 	// save status state
@@ -214,15 +351,18 @@ func (r *CertificateReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}, nil
 	}
 
-	// sync DNS endpoints for certificate validation
-	if err := r.syncDNSEndpoints(ctx, certificate); err != nil {
-		log.Error(err, "error synching DNS endpoints")
-		r.recorder.Event(certificate, core.EventTypeWarning, CertificateEventUpdateError, err.Error())
-		return ctrl.Result{}, err
+	// sync DNS endpoints for certificate validation, EMAIL validation waits
+	// for the domain owner to approve the request instead
+	if certificate.Spec.ValidationMethod() == certificatev1beta1.CertificateValidationMethodDNS && len(certificate.Status.ResourceRecords) > 0 {
+		if err := r.syncDNSEndpoints(ctx, certificate); err != nil {
+			log.Error(err, "error synching DNS endpoints")
+			r.recorder.Event(certificate, core.EventTypeWarning, CertificateEventUpdateError, err.Error())
+			return ctrl.Result{}, err
+		}
 	}
 
 	// requeue if certificate not yet issued
-	if certificate.Status.Status != certificatev1alpha1.CertificateStatusIssued {
+	if certificate.Status.Status != certificatev1beta1.CertificateStatusIssued {
 		return ctrl.Result{
 			Requeue:      true,
 			RequeueAfter: time.Second * 15,
@@ -264,73 +404,300 @@ func (r *CertificateReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
-	cfg, err := config.LoadDefaultConfig(context.Background())
-	if err != nil {
-		return err
+	if r.ACMClient == nil || r.Clients == nil {
+		cfg, err := config.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return err
+		}
+		if r.ACMClient == nil {
+			r.ACMClient = external_api_clients.NewAcmClient(acm.NewFromConfig(cfg))
+		}
+		if r.Clients == nil {
+			r.Clients = external_api_clients.NewClientFactory(cfg)
+		}
 	}
-	r.svc = external_api_clients.NewAcmClient(acm.NewFromConfig(cfg))
 
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&certificatev1alpha1.Certificate{}).
+		For(&certificatev1beta1.Certificate{}).
 		Owns(&dnsendpoint.DNSEndpoint{}).
+		Watches(&certificatev1beta1.ACMIssuer{}, handler.EnqueueRequestsFromMapFunc(r.certificatesForIssuer(certificatev1beta1.IssuerKind))).
+		Watches(&certificatev1beta1.ClusterACMIssuer{}, handler.EnqueueRequestsFromMapFunc(r.certificatesForIssuer(certificatev1beta1.ClusterIssuerKind))).
+		Watches(&certificatev1beta1.CertificateClass{}, handler.EnqueueRequestsFromMapFunc(r.certificatesForClass)).
+		Watches(&certificatev1beta1.DomainPolicy{}, handler.EnqueueRequestsFromMapFunc(r.certificatesForPolicy)).
 		Complete(r)
 }
 
-func (r *CertificateReconciler) compareACMCertificate(ctx context.Context, cert *certificatev1alpha1.Certificate) (bool, error) {
+// certificatesForPolicy maps a DomainPolicy to the Certificates of the
+// namespaces it selects.
+func (r *CertificateReconciler) certificatesForPolicy(ctx context.Context, obj client.Object) []reconcile.Request {
+	p, ok := obj.(*certificatev1beta1.DomainPolicy)
+	if !ok {
+		return nil
+	}
+
+	namespaces := &core.NamespaceList{}
+	if err := r.List(ctx, namespaces); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list namespaces of domain policy", "policy", p.Name)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, ns := range namespaces.Items {
+		if !policy.Selects(p, &ns) {
+			continue
+		}
+		certs := &certificatev1beta1.CertificateList{}
+		if err := r.List(ctx, certs, client.InNamespace(ns.Name)); err != nil {
+			log.FromContext(ctx).Error(err, "unable to list certificates of domain policy", "policy", p.Name)
+			return nil
+		}
+		for _, cert := range certs.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cert)})
+		}
+	}
+	return requests
+}
+
+// certificatesForIssuer returns a function mapping an issuer of the kind to the
+// Certificates referencing it, so they are reconciled once the issuer is ready.
+func (r *CertificateReconciler) certificatesForIssuer(kind string) handler.MapFunc {
+	return func(ctx context.Context, issuer client.Object) []reconcile.Request {
+		certs := &certificatev1beta1.CertificateList{}
+		if err := r.List(ctx, certs, client.InNamespace(issuer.GetNamespace())); err != nil {
+			log.FromContext(ctx).Error(err, "unable to list certificates of issuer", "issuer", issuer.GetName())
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, cert := range certs.Items {
+			ref := cert.Spec.IssuerRef
+			if ref == nil || ref.Name != issuer.GetName() {
+				continue
+			}
+			if ref.Kind != kind && (ref.Kind != "" || kind != certificatev1beta1.IssuerKind) {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cert)})
+		}
+		return requests
+	}
+}
+
+// compareACMCertificate returns true when the ACM certificate matches the spec
+// on all the fields of CertificateReplacedFields and is issued by the Private
+// CA of the issuer. found is false when the certificate was deleted in ACM.
+func (r *CertificateReconciler) compareACMCertificate(ctx context.Context, cert *certificatev1beta1.Certificate) (equals bool, found bool, err error) {
 	detail, err := r.getACMCertificateDetail(ctx, cert)
 	if err != nil {
 		var apiErr smithy.APIError
 		if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ResourceNotFoundException" {
-			return false, fmt.Errorf("unable to retreive certificate detail to perform comparision: %w", err)
+			return false, false, fmt.Errorf("unable to retreive certificate detail to perform comparision: %w", err)
 		}
+		// the certificate was deleted in ACM, a new one must be requested
+		return false, false, nil
 	}
 
-	if *detail.DomainName != cert.Spec.CommonName {
-		return false, nil
+	issuer, err := r.getIssuer(ctx, cert)
+	if err != nil {
+		return false, true, err
 	}
-	if len(detail.SubjectAlternativeNames) != len(cert.Spec.SubjectAlternativeNames) {
-		return false, nil
+	privateCA := ""
+	if issuer != nil {
+		privateCA = issuer.GetSpec().PrivateCAARN
 	}
+	if aws.ToString(detail.CertificateAuthorityArn) != privateCA {
+		return false, true, nil
+	}
+
+	return len(CertificateReplacedFields(issuedCertificateSpec(cert, detail), &cert.Spec)) == 0, true, nil
+}
 
-	dnsNames := map[string]bool{}
-	for _, domain := range detail.SubjectAlternativeNames {
-		dnsNames[domain] = true
+// needsACMComparison returns true when the ACM certificate must be described
+// to know if it still matches the spec: the spec changed since the certificate
+// was requested or the last verification is older than VerifyInterval.
+func (r *CertificateReconciler) needsACMComparison(cert *certificatev1beta1.Certificate) bool {
+	if r.VerifyInterval <= 0 || cert.Status.LastVerifiedTime == nil {
+		return true
+	}
+	if cert.Status.SpecHash != certificateSpecHash(&cert.Spec) {
+		return true
 	}
+	return time.Since(cert.Status.LastVerifiedTime.Time) >= r.VerifyInterval
+}
 
-	for _, dnsName := range cert.Spec.SubjectAlternativeNames {
-		if _, ok := dnsNames[dnsName]; !ok {
-			return false, nil
-		}
+// specSettleDelay returns how long to wait before acting on a spec change. The
+// pending spec is recorded in the status and the wait restarts every time the
+// spec changes again.
+func (r *CertificateReconciler) specSettleDelay(cert *certificatev1beta1.Certificate) time.Duration {
+	if r.SpecSettleWindow <= 0 {
+		return 0
 	}
 
-	return true, nil
+	now := time.Now()
+	hash := certificateSpecHash(&cert.Spec)
+	if cert.Status.PendingSpecHash != hash || cert.Status.PendingSpecSince == nil {
+		cert.Status.PendingSpecHash = hash
+		cert.Status.PendingSpecSince = &metav1.Time{Time: now}
+		return r.SpecSettleWindow
+	}
+
+	return cert.Status.PendingSpecSince.Add(r.SpecSettleWindow).Sub(now)
 }
 
-func (r *CertificateReconciler) requestACMCertificate(ctx context.Context, cert *certificatev1alpha1.Certificate) error {
-	acmReq := newRequestCertificateInput(cert)
-	resp, err := r.svc.RequestCertificate(ctx, acmReq)
+func (r *CertificateReconciler) requestACMCertificate(ctx context.Context, cert *certificatev1beta1.Certificate) error {
+	issuer, err := r.getIssuer(ctx, cert)
+	if err != nil {
+		return err
+	}
+	acmClient, err := r.acmClientFor(ctx, cert)
+	if err != nil {
+		return err
+	}
+
+	// the budget is counted per account and region, the ones of the issuer when
+	// there is one, an empty region being the default one of the budget
+	var account, region string
+	if r.RequestBudget != nil {
+		region = cert.Spec.Region
+		if issuer != nil {
+			account = issuer.GetStatus().Account
+			if region == "" {
+				region = issuer.GetSpec().Region
+			}
+		} else {
+			account, err = r.RequestBudget.CallerAccount(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		if err := r.RequestBudget.Check(ctx, account, region); err != nil {
+			return err
+		}
+	}
+
+	acmReq := newRequestCertificateInput(cert, issuer)
+	resp, err := acmClient.RequestCertificate(ctx, acmReq, acmOptions(cert)...)
 	if err != nil {
 		return fmt.Errorf("unable to request certificate: %w", err)
 	}
 
 	cert.Status.CertificateArn = *resp.CertificateArn
-	cert.Status.Status = certificatev1alpha1.CertificateStatusRequested
+	cert.Status.Status = certificatev1beta1.CertificateStatusRequested
+	cert.Status.SpecHash = certificateSpecHash(&cert.Spec)
+	cert.Status.LastVerifiedTime = &metav1.Time{Time: time.Now()}
+	recordRequestedLocation(cert)
+
+	if r.RequestBudget != nil {
+		if err := r.RequestBudget.Record(ctx, account, region); err != nil {
+			// the certificate is requested at this point, only the accounting is off
+			log.FromContext(ctx).Error(err, "unable to record certificate request in budget")
+		}
+	}
+
+	return nil
+}
+
+func (r *CertificateReconciler) handleRequestError(ctx context.Context, cert *certificatev1beta1.Certificate, err error) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	var budgetErr *RequestBudgetExceededError
+	if errors.As(err, &budgetErr) {
+		log.Info("certificate request refused", "reason", err.Error())
+		r.recorder.Event(cert, core.EventTypeWarning, CertificateEventRequestBudget, err.Error())
+		setCertificateCondition(cert, metav1.ConditionFalse, certificatev1beta1.CertificateReasonRequestBudgetExceeded, err.Error())
+		if err := r.updateWithStatus(ctx, cert); err != nil {
+			log.Error(err, "unable to update status")
+		}
+		return ctrl.Result{RequeueAfter: budgetErr.RetryAfter}, nil
+	}
+
+	return r.handleACMError(ctx, cert, CertificateEventRequestError, err)
+}
+
+// handleACMError reports an ACM API error on the Certificate and picks the
+// requeue strategy matching the class of the error.
+func (r *CertificateReconciler) handleACMError(ctx context.Context, cert *certificatev1beta1.Certificate, eventReason string, err error) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	class := ClassifyACMError(err)
+	log.Error(err, "ACM API call failed", "class", class)
+	r.recorder.Event(cert, core.EventTypeWarning, eventReason, err.Error())
+
+	var result ctrl.Result
+	var resultErr error
+	switch class {
+	case ACMErrorTerminal:
+		// retrying will not help until the Certificate is changed
+		cert.Status.Status = certificatev1beta1.CertificateStatusError
+	case ACMErrorPermission:
+		cert.Status.Status = certificatev1beta1.CertificateStatusError
+		result.RequeueAfter = permissionErrorRetryInterval
+	case ACMErrorThrottling:
+		result.RequeueAfter = r.throttlingBackoff().When(client.ObjectKeyFromObject(cert))
+	default:
+		resultErr = err
+	}
+
+	setCertificateCondition(cert, metav1.ConditionFalse, class.ConditionReason(), err.Error())
+	if err := r.updateWithStatus(ctx, cert); err != nil {
+		log.Error(err, "unable to update status")
+	}
+	return result, resultErr
+}
+
+// throttlingBackoff returns the per Certificate delays used to retry throttled ACM calls.
+func (r *CertificateReconciler) throttlingBackoff() workqueue.TypedRateLimiter[types.NamespacedName] {
+	r.throttlingOnce.Do(func() {
+		r.throttling = workqueue.NewTypedItemExponentialFailureRateLimiter[types.NamespacedName](throttlingBaseDelay, throttlingMaxDelay)
+	})
+	return r.throttling
+}
+
+// certificateInfoUpToDate returns true when the status holds the information of
+// an issued ACM certificate matching the spec, so it is not described again.
+func certificateInfoUpToDate(cert *certificatev1beta1.Certificate) bool {
+	return cert.Status.Status == certificatev1beta1.CertificateStatusIssued &&
+		cert.Status.SpecHash == certificateSpecHash(&cert.Spec)
+}
 
+// updateSpecHashTag sets the spec hash tag of the ACM certificate to the hash
+// of the spec, for a spec change the certificate still matches.
+func (r *CertificateReconciler) updateSpecHashTag(ctx context.Context, cert *certificatev1beta1.Certificate) error {
+	acmClient, err := r.acmClientFor(ctx, cert)
+	if err != nil {
+		return err
+	}
+
+	_, err = acmClient.AddTagsToCertificate(ctx, &acm.AddTagsToCertificateInput{
+		CertificateArn: aws.String(cert.Status.CertificateArn),
+		Tags: []acmtypes.Tag{{
+			Key:   aws.String(TagCertificateSpecHash),
+			Value: aws.String(certificateSpecHash(&cert.Spec)),
+		}},
+	}, acmOptions(cert)...)
+	if err != nil {
+		return fmt.Errorf("unable to update spec hash tag of certificate with ARN %s: %w", cert.Status.CertificateArn, err)
+	}
 	return nil
 }
 
-func (r *CertificateReconciler) updateCertificateInfo(ctx context.Context, cert *certificatev1alpha1.Certificate) (bool, error) {
+func (r *CertificateReconciler) updateCertificateInfo(ctx context.Context, cert *certificatev1beta1.Certificate) (bool, error) {
 	detail, err := r.getACMCertificateDetail(ctx, cert)
 	if err != nil {
 		return true, fmt.Errorf("unable to retreive certificate detail for update: %w", err)
 	}
 
-	records := []certificatev1alpha1.ResourceRecord{}
+	records := []certificatev1beta1.ResourceRecord{}
 	for _, d := range detail.DomainValidationOptions {
+		if cert.Spec.ValidationMethod() != certificatev1beta1.CertificateValidationMethodDNS || detail.Type == acmtypes.CertificateTypePrivate {
+			// approval emails are sent by ACM and private certificates are not
+			// validated, there are no records to publish
+			break
+		}
 		if d.ResourceRecord == nil {
 			return true, nil
 		}
-		records = append(records, certificatev1alpha1.ResourceRecord{
+		records = append(records, certificatev1beta1.ResourceRecord{
 			Name:  *d.ResourceRecord.Name,
 			Type:  string(d.ResourceRecord.Type),
 			Value: *d.ResourceRecord.Value,
@@ -352,9 +719,22 @@ func (r *CertificateReconciler) updateCertificateInfo(ctx context.Context, cert
 	return false, nil
 }
 
-func (r *CertificateReconciler) getACMCertificateDetail(ctx context.Context, cert *certificatev1alpha1.Certificate) (*acmtypes.CertificateDetail, error) {
+// acmOptions returns the ACM client options of the calls made for a Certificate.
+func acmOptions(cert *certificatev1beta1.Certificate) []func(*acm.Options) {
+	if cert.Spec.Region == "" {
+		return nil
+	}
+	return []func(*acm.Options){func(o *acm.Options) { o.Region = cert.Spec.Region }}
+}
+
+func (r *CertificateReconciler) getACMCertificateDetail(ctx context.Context, cert *certificatev1beta1.Certificate) (*acmtypes.CertificateDetail, error) {
+	acmClient, err := r.acmClientFor(ctx, cert)
+	if err != nil {
+		return nil, err
+	}
+
 	input := &acm.DescribeCertificateInput{CertificateArn: aws.String(cert.Status.CertificateArn)}
-	resp, err := r.svc.DescribeCertificate(ctx, input)
+	resp, err := acmClient.DescribeCertificate(ctx, input, acmOptions(cert)...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to retreive certificate with ARN %s: %w", cert.Status.CertificateArn, err)
 	}
@@ -362,11 +742,12 @@ func (r *CertificateReconciler) getACMCertificateDetail(ctx context.Context, cer
 	return resp.Certificate, nil
 }
 
-func newRequestCertificateInput(cert *certificatev1alpha1.Certificate) *acm.RequestCertificateInput {
+func newRequestCertificateInput(cert *certificatev1beta1.Certificate, issuer certificatev1beta1.GenericIssuer) *acm.RequestCertificateInput {
 	req := &acm.RequestCertificateInput{
 		DomainName:              aws.String(cert.Spec.CommonName),
 		SubjectAlternativeNames: cert.Spec.SubjectAlternativeNames,
-		ValidationMethod:        acmtypes.ValidationMethodDns,
+		ValidationMethod:        acmtypes.ValidationMethod(cert.Spec.ValidationMethod()),
+		KeyAlgorithm:            acmtypes.KeyAlgorithm(cert.Spec.KeyAlgorithm()),
 		Tags: []acmtypes.Tag{
 			{
 				Key:   aws.String(TagCertificateOwner),
@@ -377,23 +758,94 @@ func newRequestCertificateInput(cert *certificatev1alpha1.Certificate) *acm.Requ
 			}, {
 				Key:   aws.String(TagCertificateName),
 				Value: aws.String(cert.Name),
+			}, {
+				Key:   aws.String(TagCertificateSpecHash),
+				Value: aws.String(certificateSpecHash(&cert.Spec)),
 			},
 		},
 	}
 
+	if cert.Spec.Options != nil && cert.Spec.Options.CertificateTransparencyLogging != "" || cert.Spec.Export != nil {
+		req.Options = &acmtypes.CertificateOptions{}
+		switch {
+		case cert.Spec.Options == nil:
+		case cert.Spec.Options.CertificateTransparencyLogging == certificatev1beta1.CertificateTransparencyLoggingEnabled:
+			req.Options.CertificateTransparencyLoggingPreference = acmtypes.CertificateTransparencyLoggingPreferenceEnabled
+		case cert.Spec.Options.CertificateTransparencyLogging == certificatev1beta1.CertificateTransparencyLoggingDisabled:
+			req.Options.CertificateTransparencyLoggingPreference = acmtypes.CertificateTransparencyLoggingPreferenceDisabled
+		}
+		if cert.Spec.Export != nil && cert.Spec.Export.Enabled {
+			req.Options.Export = acmtypes.CertificateExportEnabled
+		}
+	}
+
+	tags := map[string]string{}
+	if issuer != nil {
+		if arn := issuer.GetSpec().PrivateCAARN; arn != "" {
+			// private certificates are issued by the CA without domain validation
+			req.CertificateAuthorityArn = aws.String(arn)
+			req.ValidationMethod = ""
+		}
+		maps.Copy(tags, issuer.GetSpec().Tags)
+	}
+	maps.Copy(tags, cert.Spec.Tags)
+
+	// user tags can not override the ones used to track the certificate
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		if strings.HasPrefix(k, TagReservedPrefix) {
+			continue
+		}
+		req.Tags = append(req.Tags, acmtypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+
 	return req
 }
 
-func (r *CertificateReconciler) deleteACMCertificate(ctx context.Context, cert *certificatev1alpha1.Certificate) error {
+// releaseACMCertificate deletes the ACM certificate of a deleted Certificate,
+// unless its deletion policy retains it. A retained certificate loses its owner
+// tag so the cleanup job does not delete it as an orphan.
+func (r *CertificateReconciler) releaseACMCertificate(ctx context.Context, cert *certificatev1beta1.Certificate) error {
+	if cert.Spec.DeletionPolicy != certificatev1beta1.CertificateDeletionPolicyRetain {
+		return r.deleteACMCertificate(ctx, cert)
+	}
 	if cert.Status.CertificateArn == "" {
 		return nil
 	}
+	acmClient, err := r.acmClientFor(ctx, cert)
+	if err != nil {
+		return err
+	}
+
+	_, err = acmClient.RemoveTagsFromCertificate(ctx, &acm.RemoveTagsFromCertificateInput{
+		CertificateArn: aws.String(cert.Status.CertificateArn),
+		Tags:           []acmtypes.Tag{{Key: aws.String(TagCertificateOwner)}},
+	}, acmOptions(cert)...)
+	if err != nil {
+		var apiErr smithy.APIError
+		if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ResourceNotFoundException" {
+			return fmt.Errorf("unable to release certificate with ARN: %s: %w", cert.Status.CertificateArn, err)
+		}
+	}
+	log.FromContext(ctx).Info("ACM certificate retained", "ARN", cert.Status.CertificateArn)
+
+	return nil
+}
+
+func (r *CertificateReconciler) deleteACMCertificate(ctx context.Context, cert *certificatev1beta1.Certificate) error {
+	if cert.Status.CertificateArn == "" {
+		return nil
+	}
+
+	acmClient, err := r.acmClientFor(ctx, cert)
+	if err != nil {
+		return err
+	}
 
 	input := &acm.DeleteCertificateInput{
 		CertificateArn: aws.String(cert.Status.CertificateArn),
 	}
 
-	_, err := r.svc.DeleteCertificate(ctx, input)
+	_, err = acmClient.DeleteCertificate(ctx, input, acmOptions(cert)...)
 	if err != nil {
 		var apiErr smithy.APIError
 		if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ResourceNotFoundException" {
@@ -404,16 +856,21 @@ func (r *CertificateReconciler) deleteACMCertificate(ctx context.Context, cert *
 	return nil
 }
 
-func (r *CertificateReconciler) cleanupACMCertificates(ctx context.Context, cert *certificatev1alpha1.Certificate) (int, error) {
+func (r *CertificateReconciler) cleanupACMCertificates(ctx context.Context, cert *certificatev1beta1.Certificate) (int, error) {
 	var result error
 
 	nbCleanedUp := 0
 
+	acmClient, err := r.acmClientFor(ctx, cert)
+	if err != nil {
+		return nbCleanedUp, err
+	}
+
 	input := &acm.ListCertificatesInput{
 		// MaxItems:            new(int32),
 		// NextToken:           new(string),
 	}
-	output, err := r.svc.ListCertificates(ctx, input)
+	output, err := acmClient.ListCertificates(ctx, input, acmOptions(cert)...)
 	if err != nil {
 		return nbCleanedUp, fmt.Errorf("unable to list certificates: %w", err)
 	}
@@ -426,7 +883,7 @@ func (r *CertificateReconciler) cleanupACMCertificates(ctx context.Context, cert
 		input := &acm.ListTagsForCertificateInput{
 			CertificateArn: summary.CertificateArn,
 		}
-		output, err := r.svc.ListTagsForCertificate(ctx, input)
+		output, err := acmClient.ListTagsForCertificate(ctx, input, acmOptions(cert)...)
 		if err != nil {
 			return nbCleanedUp, fmt.Errorf("unable to retrieve list of tags for certificate %s/%s: %w", cert.Namespace, cert.Name, err)
 		}
@@ -437,8 +894,15 @@ func (r *CertificateReconciler) cleanupACMCertificates(ctx context.Context, cert
 		}
 
 		if tags[TagCertificateOwner] == ACMManagerOwnerName && tags[TagCertificateNamespace] == cert.Namespace && tags[TagCertificateName] == cert.Name {
-			if err := r.deleteACMCertificate(ctx, &certificatev1alpha1.Certificate{
-				Status: certificatev1alpha1.CertificateStatus{
+			if err := r.deleteACMCertificate(ctx, &certificatev1beta1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: cert.Namespace,
+				},
+				Spec: certificatev1beta1.CertificateSpec{
+					IssuerRef: cert.Spec.IssuerRef,
+					Region:    cert.Spec.Region,
+				},
+				Status: certificatev1beta1.CertificateStatus{
 					CertificateArn: *summary.CertificateArn,
 				},
 			}); err != nil {
@@ -453,7 +917,7 @@ func (r *CertificateReconciler) cleanupACMCertificates(ctx context.Context, cert
 	return nbCleanedUp, result
 }
 
-func (r *CertificateReconciler) syncDNSEndpoints(ctx context.Context, cert *certificatev1alpha1.Certificate) error {
+func (r *CertificateReconciler) syncDNSEndpoints(ctx context.Context, cert *certificatev1beta1.Certificate) error {
 	dnsEndpoint := &dnsendpoint.DNSEndpoint{}
 	nsName := types.NamespacedName{Name: cert.Name, Namespace: cert.Namespace}
 	newEndpoint := false
@@ -488,10 +952,20 @@ func (r *CertificateReconciler) syncDNSEndpoints(ctx context.Context, cert *cert
 	return nil
 }
 
-func (r *CertificateReconciler) updateWithStatus(ctx context.Context, cert *certificatev1alpha1.Certificate) error {
+func (r *CertificateReconciler) updateWithStatus(ctx context.Context, cert *certificatev1beta1.Certificate) error {
 	return updateCertificateWithStatus(ctx, r.certClient, cert)
 }
 
+func setCertificateCondition(cert *certificatev1beta1.Certificate, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&cert.Status.Conditions, metav1.Condition{
+		Type:               certificatev1beta1.CertificateConditionReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: cert.Generation,
+	})
+}
+
 // Helper functions to check and remove string from a slice of strings.
 func containsString(slice []string, s string) bool {
 	for _, item := range slice {
@@ -512,96 +986,29 @@ func removeString(slice []string, s string) (result []string) {
 	return
 }
 
-func convertFrom(status acmtypes.CertificateStatus) certificatev1alpha1.CertificateStatusType {
+func convertFrom(status acmtypes.CertificateStatus) certificatev1beta1.CertificateStatusType {
 	switch status {
 	case acmtypes.CertificateStatusPendingValidation:
-		return certificatev1alpha1.CertificateStatusPendingValidation
+		return certificatev1beta1.CertificateStatusPendingValidation
 	case acmtypes.CertificateStatusIssued:
-		return certificatev1alpha1.CertificateStatusIssued
+		return certificatev1beta1.CertificateStatusIssued
 	case acmtypes.CertificateStatusInactive:
-		return certificatev1alpha1.CertificateStatusInactive
+		return certificatev1beta1.CertificateStatusInactive
 	case acmtypes.CertificateStatusExpired:
-		return certificatev1alpha1.CertificateStatusExpired
+		return certificatev1beta1.CertificateStatusExpired
 	case acmtypes.CertificateStatusValidationTimedOut:
-		return certificatev1alpha1.CertificateStatusValidationTimedOut
+		return certificatev1beta1.CertificateStatusValidationTimedOut
 	case acmtypes.CertificateStatusRevoked:
-		return certificatev1alpha1.CertificateStatusRevoked
+		return certificatev1beta1.CertificateStatusRevoked
 	case acmtypes.CertificateStatusFailed:
-		return certificatev1alpha1.CertificateStatusFailed
+		return certificatev1beta1.CertificateStatusFailed
 	default:
-		return certificatev1alpha1.CertificateStatusUnknown
+		return certificatev1beta1.CertificateStatusUnknown
 	}
 }
 
-func StartACMCertificateCleanupJob() {
-	ticker := time.NewTicker(ACMCertificateCleanupInterval)
-	go func() {
-		for range ticker.C {
-			cleanupOrphanACMCertificates()
-		}
-	}()
-}
-
-func cleanupOrphanACMCertificates() {
-	ctx := context.Background()
-	log := log.FromContext(ctx).WithName("background cleanup")
-
-	cfg, err := config.LoadDefaultConfig(ctx)
-	if err != nil {
-		log.Error(err, "unable to load config")
-		return
-	}
-	acmClient := external_api_clients.NewAcmClient(acm.NewFromConfig(cfg))
-
-	certClient, err := external_api_clients.NewCertificateRestClient(ctx)
-
-	input := &acm.ListCertificatesInput{
-		// MaxItems:            new(int32),
-		// NextToken:           new(string),
-	}
-	output, err := acmClient.ListCertificates(ctx, input)
-	if err != nil {
-		log.Error(err, "unable to list certificates")
-		return
-	}
-	for _, summary := range output.CertificateSummaryList {
-		log := log.WithValues("ARN", *summary.CertificateArn)
-
-		input := &acm.ListTagsForCertificateInput{
-			CertificateArn: summary.CertificateArn,
-		}
-		output, err := acmClient.ListTagsForCertificate(ctx, input)
-		if err != nil {
-			log.Error(err, "unable to list certificate tags")
-			continue
-		}
-
-		tags := make(map[string]string, len(output.Tags))
-		for _, t := range output.Tags {
-			tags[*t.Key] = *t.Value
-		}
-
-		if tags[TagCertificateOwner] != ACMManagerOwnerName {
-			continue
-		}
-
-		_, err = certClient.GetCertificateForNamespace(ctx, tags[TagCertificateNamespace], tags[TagCertificateName], metav1.GetOptions{})
-		if err != nil {
-			if apierrors.IsNotFound(err) {
-				if _, err := acmClient.DeleteCertificate(ctx, &acm.DeleteCertificateInput{
-					CertificateArn: summary.CertificateArn,
-				}); err != nil {
-					log.Error(err, "unable to delete unused owned certificate")
-					continue
-				}
-				log.Info("certificate deleted in ACM")
-			}
-		}
-	}
-}
-
-func updateCertificate(ctx context.Context, certClient certificateclient.Interface, cert *certificatev1alpha1.Certificate) error {
-	if _, err := certClient.AcmmanagerV1alpha1().Certificates(cert.Namespace).
+func updateCertificate(ctx context.Context, certClient certificateclient.Interface, cert *certificatev1beta1.Certificate) error {
+	if _, err := certClient.AcmmanagerV1beta1().Certificates(cert.Namespace).
 		Apply(ctx,
 			ApplyConfigurationFromCertificate(cert),
 			metav1.ApplyOptions{FieldManager: ACMManagerFieldManager, Force: true}); err != nil {
@@ -610,8 +1017,8 @@ func updateCertificate(ctx context.Context, certClient certificateclient.Interfa
 	return nil
 }
 
-func updateCertificateWithStatus(ctx context.Context, certClient certificateclient.Interface, cert *certificatev1alpha1.Certificate) error {
-	if _, err := certClient.AcmmanagerV1alpha1().Certificates(cert.Namespace).
+func updateCertificateWithStatus(ctx context.Context, certClient certificateclient.Interface, cert *certificatev1beta1.Certificate) error {
+	if _, err := certClient.AcmmanagerV1beta1().Certificates(cert.Namespace).
 		ApplyStatus(ctx,
 			ApplyConfigurationFromCertificate(cert),
 			metav1.ApplyOptions{FieldManager: ACMManagerFieldManager, Force: true}); err != nil {
@@ -620,7 +1027,7 @@ func updateCertificateWithStatus(ctx context.Context, certClient certificateclie
 	return nil
 }
 
-func ApplyConfigurationFromCertificate(c *certificatev1alpha1.Certificate) *certac.CertificateApplyConfiguration {
+func ApplyConfigurationFromCertificate(c *certificatev1beta1.Certificate) *certac.CertificateApplyConfiguration {
 	rr := []*certac.ResourceRecordApplyConfiguration{}
 	for _, d := range c.Status.ResourceRecords {
 		rr = append(rr, certac.ResourceRecord().
@@ -640,10 +1047,82 @@ func ApplyConfigurationFromCertificate(c *certificatev1alpha1.Certificate) *cert
 	if c.Status.NotBefore != nil {
 		status.WithNotBefore(*c.Status.NotBefore)
 	}
+	if c.Status.SpecHash != "" {
+		status.WithSpecHash(c.Status.SpecHash)
+	}
+	if c.Status.LastVerifiedTime != nil {
+		status.WithLastVerifiedTime(*c.Status.LastVerifiedTime)
+	}
+	if c.Status.PendingSpecHash != "" {
+		status.WithPendingSpecHash(c.Status.PendingSpecHash)
+	}
+	if c.Status.PendingSpecSince != nil {
+		status.WithPendingSpecSince(*c.Status.PendingSpecSince)
+	}
+	if c.Status.RequestedIssuerRef != nil {
+		ref := certac.IssuerReference().WithName(c.Status.RequestedIssuerRef.Name)
+		if c.Status.RequestedIssuerRef.Kind != "" {
+			ref.WithKind(c.Status.RequestedIssuerRef.Kind)
+		}
+		status.WithRequestedIssuerRef(ref)
+	}
+	if c.Status.RequestedRegion != "" {
+		status.WithRequestedRegion(c.Status.RequestedRegion)
+	}
+	for _, cond := range c.Status.Conditions {
+		status.WithConditions(metav1ac.Condition().
+			WithType(cond.Type).
+			WithStatus(cond.Status).
+			WithReason(cond.Reason).
+			WithMessage(cond.Message).
+			WithObservedGeneration(cond.ObservedGeneration).
+			WithLastTransitionTime(cond.LastTransitionTime))
+	}
 
 	spec := certac.CertificateSpec().
 		WithCommonName(c.Spec.CommonName).
 		WithSubjectAlternativeNames(c.Spec.SubjectAlternativeNames...)
+	if c.Spec.CertificateClassName != "" {
+		spec.WithCertificateClassName(c.Spec.CertificateClassName)
+	}
+	if c.Spec.IssuerRef != nil {
+		ref := certac.IssuerReference().WithName(c.Spec.IssuerRef.Name)
+		if c.Spec.IssuerRef.Kind != "" {
+			ref.WithKind(c.Spec.IssuerRef.Kind)
+		}
+		spec.WithIssuerRef(ref)
+	}
+	if c.Spec.Region != "" {
+		spec.WithRegion(c.Spec.Region)
+	}
+	// empty enum fields are left out, the CRD rejecting empty values
+	if c.Spec.Validation != nil {
+		validation := certac.CertificateValidation()
+		if c.Spec.Validation.Method != "" {
+			validation.WithMethod(c.Spec.Validation.Method)
+		}
+		spec.WithValidation(validation)
+	}
+	if c.Spec.Options != nil {
+		options := certac.CertificateOptions()
+		if c.Spec.Options.KeyAlgorithm != "" {
+			options.WithKeyAlgorithm(c.Spec.Options.KeyAlgorithm)
+		}
+		if c.Spec.Options.CertificateTransparencyLogging != "" {
+			options.WithCertificateTransparencyLogging(c.Spec.Options.CertificateTransparencyLogging)
+		}
+		spec.WithOptions(options)
+	}
+	if c.Spec.Export != nil {
+		spec.WithExport(certac.CertificateExport().
+			WithEnabled(c.Spec.Export.Enabled))
+	}
+	if len(c.Spec.Tags) > 0 {
+		spec.WithTags(c.Spec.Tags)
+	}
+	if c.Spec.DeletionPolicy != "" {
+		spec.WithDeletionPolicy(c.Spec.DeletionPolicy)
+	}
 
 	return certac.Certificate(c.Name, c.Namespace).
 		WithSpec(spec).
@@ -21,7 +21,7 @@ import (
 	"time"
 	"vdesjardins/acm-manager/pkg/controllers/external_api_clients"
 
-	certificatev1alpha1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1alpha1"
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/acm"
@@ -79,6 +79,14 @@ func (a *acmClientMock) ListTagsForCertificate(ctx context.Context, params *acm.
 	return &acm.ListTagsForCertificateOutput{}, nil
 }
 
+func (a *acmClientMock) AddTagsToCertificate(ctx context.Context, params *acm.AddTagsToCertificateInput, optFns ...func(*acm.Options)) (*acm.AddTagsToCertificateOutput, error) {
+	return &acm.AddTagsToCertificateOutput{}, nil
+}
+
+func (a *acmClientMock) RemoveTagsFromCertificate(ctx context.Context, params *acm.RemoveTagsFromCertificateInput, optFns ...func(*acm.Options)) (*acm.RemoveTagsFromCertificateOutput, error) {
+	return &acm.RemoveTagsFromCertificateOutput{}, nil
+}
+
 var _ = Describe("Certificate controller", func() {
 	const (
 		timeout  = time.Second * 10
@@ -97,7 +105,7 @@ var _ = Describe("Certificate controller", func() {
 			Expect(k8sClient.Create(ctx, cert)).Should(Succeed())
 
 			certLookupKey := types.NamespacedName{Name: certName, Namespace: certNamespace}
-			createdCert := &certificatev1alpha1.Certificate{}
+			createdCert := &certificatev1beta1.Certificate{}
 
 			By("By checking that certificate arn is set")
 			Eventually(func() bool {
@@ -122,7 +130,7 @@ var _ = Describe("Certificate controller", func() {
 			Expect(k8sClient.Create(ctx, cert)).Should(Succeed())
 
 			certLookupKey := types.NamespacedName{Name: certName, Namespace: certNamespace}
-			createdCert := &certificatev1alpha1.Certificate{}
+			createdCert := &certificatev1beta1.Certificate{}
 
 			By("By checking that resource records are set")
 			Eventually(func() bool {
@@ -172,17 +180,140 @@ var _ = Describe("Certificate controller", func() {
 	})
 })
 
-func newCert(certName, certNamespace string) *certificatev1alpha1.Certificate {
-	return &certificatev1alpha1.Certificate{
+// acmClientReleaseMock records how the ACM certificate of a deleted Certificate is released
+type acmClientReleaseMock struct {
+	acmClientMock
+	deleted     bool
+	removedTags []string
+}
+
+func (a *acmClientReleaseMock) DeleteCertificate(ctx context.Context, params *acm.DeleteCertificateInput, optFns ...func(*acm.Options)) (*acm.DeleteCertificateOutput, error) {
+	a.deleted = true
+	return &acm.DeleteCertificateOutput{}, nil
+}
+
+func (a *acmClientReleaseMock) RemoveTagsFromCertificate(ctx context.Context, params *acm.RemoveTagsFromCertificateInput, optFns ...func(*acm.Options)) (*acm.RemoveTagsFromCertificateOutput, error) {
+	for _, t := range params.Tags {
+		a.removedTags = append(a.removedTags, *t.Key)
+	}
+	return &acm.RemoveTagsFromCertificateOutput{}, nil
+}
+
+var _ = Describe("Certificate ACM options", func() {
+	It("Should request the certificate with the key algorithm and tags of the spec", func() {
+		cert := newCert("test-cert", "default")
+		cert.Spec.Options = &certificatev1beta1.CertificateOptions{
+			KeyAlgorithm:                   certificatev1beta1.CertificateKeyAlgorithmECPrime256v1,
+			CertificateTransparencyLogging: certificatev1beta1.CertificateTransparencyLoggingDisabled,
+		}
+		cert.Spec.Export = &certificatev1beta1.CertificateExport{Enabled: true}
+		cert.Spec.Tags = map[string]string{
+			"team":              "platform",
+			TagCertificateOwner: "someone-else",
+		}
+
+		input := newRequestCertificateInput(cert, nil)
+		Expect(input.KeyAlgorithm).To(Equal(acmtypes.KeyAlgorithmEcPrime256v1))
+		Expect(input.ValidationMethod).To(Equal(acmtypes.ValidationMethodDns))
+		Expect(input.Options.CertificateTransparencyLoggingPreference).To(Equal(acmtypes.CertificateTransparencyLoggingPreferenceDisabled))
+		Expect(input.Options.Export).To(Equal(acmtypes.CertificateExportEnabled))
+
+		tags := map[string]string{}
+		for _, t := range input.Tags {
+			tags[*t.Key] = *t.Value
+		}
+		Expect(tags).To(HaveKeyWithValue("team", "platform"))
+		Expect(tags).To(HaveKeyWithValue(TagCertificateOwner, ACMManagerOwnerName))
+		Expect(input.Tags).To(HaveLen(5))
+	})
+
+	It("Should delete or retain the ACM certificate according to the deletion policy", func() {
+		cert := newCert("test-cert", "default")
+		cert.Status.CertificateArn = "test-arn"
+
+		By("deleting it by default")
+		mock := &acmClientReleaseMock{}
+		r := &CertificateReconciler{ACMClient: mock}
+		Expect(r.releaseACMCertificate(context.Background(), cert)).To(Succeed())
+		Expect(mock.deleted).To(BeTrue())
+
+		By("removing the owner tag when retained")
+		cert.Spec.DeletionPolicy = certificatev1beta1.CertificateDeletionPolicyRetain
+		mock = &acmClientReleaseMock{}
+		r = &CertificateReconciler{ACMClient: mock}
+		Expect(r.releaseACMCertificate(context.Background(), cert)).To(Succeed())
+		Expect(mock.deleted).To(BeFalse())
+		Expect(mock.removedTags).To(ConsistOf(TagCertificateOwner))
+	})
+
+	It("Should replace the ACM certificate when a field it was requested with changes", func() {
+		cert := newCert("test-cert", "default")
+		cert.Spec.Options = &certificatev1beta1.CertificateOptions{KeyAlgorithm: certificatev1beta1.CertificateKeyAlgorithmECPrime256v1}
+		recordRequestedLocation(cert)
+		detail := &acmtypes.CertificateDetail{
+			DomainName:              aws.String("test.local"),
+			SubjectAlternativeNames: []string{"test.local"},
+			KeyAlgorithm:            acmtypes.KeyAlgorithmEcPrime256v1,
+			DomainValidationOptions: []acmtypes.DomainValidation{{ValidationMethod: acmtypes.ValidationMethodDns}},
+			Options: &acmtypes.CertificateOptions{
+				CertificateTransparencyLoggingPreference: acmtypes.CertificateTransparencyLoggingPreferenceEnabled,
+			},
+		}
+		Expect(CertificateReplacedFields(issuedCertificateSpec(cert, detail), &cert.Spec)).To(BeEmpty())
+
+		cert.Spec.Validation = &certificatev1beta1.CertificateValidation{Method: certificatev1beta1.CertificateValidationMethodEmail}
+		cert.Spec.Options.CertificateTransparencyLogging = certificatev1beta1.CertificateTransparencyLoggingDisabled
+		cert.Spec.Export = &certificatev1beta1.CertificateExport{Enabled: true}
+		Expect(CertificateReplacedFields(issuedCertificateSpec(cert, detail), &cert.Spec)).To(ConsistOf(
+			"validation.method", "options.certificateTransparencyLogging", "export.enabled"))
+
+		By("replacing it without describing it when the issuer or the region changes")
+		Expect(requestedLocationChanged(cert)).To(BeFalse())
+		cert.Spec.Region = "us-east-1"
+		Expect(requestedLocationChanged(cert)).To(BeTrue())
+		cert.Spec.Region = ""
+		cert.Spec.IssuerRef = &certificatev1beta1.IssuerReference{Name: "other"}
+		Expect(requestedLocationChanged(cert)).To(BeTrue())
+	})
+
+	It("Should record the location of the certificates requested before it was recorded", func() {
+		cert := newCert("test-cert", "default")
+		cert.Spec.IssuerRef = &certificatev1beta1.IssuerReference{Name: "issuer"}
+		cert.Status.SpecHash = certificateSpecHash(&cert.Spec)
+
+		Expect(requestedLocationChanged(cert)).To(BeFalse())
+		Expect(cert.Status.RequestedIssuerRef).To(Equal(cert.Spec.IssuerRef))
+
+		By("keeping the recorded location once the spec changes")
+		cert.Spec.IssuerRef = &certificatev1beta1.IssuerReference{Name: "issuer", Kind: certificatev1beta1.IssuerKind}
+		cert.Spec.SubjectAlternativeNames = []string{"other.local"}
+		Expect(requestedLocationChanged(cert)).To(BeFalse())
+	})
+
+	It("Should call ACM in the region of the spec", func() {
+		cert := newCert("test-cert", "default")
+		Expect(acmOptions(cert)).To(BeEmpty())
+
+		cert.Spec.Region = "us-east-1"
+		o := acm.Options{Region: "ca-central-1"}
+		for _, fn := range acmOptions(cert) {
+			fn(&o)
+		}
+		Expect(o.Region).To(Equal("us-east-1"))
+	})
+})
+
+func newCert(certName, certNamespace string) *certificatev1beta1.Certificate {
+	return &certificatev1beta1.Certificate{
 		TypeMeta: metav1.TypeMeta{
-			APIVersion: "acm-manager/v1alpha1",
+			APIVersion: "acm-manager/v1beta1",
 			Kind:       "Certificate",
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      certName,
 			Namespace: certNamespace,
 		},
-		Spec: certificatev1alpha1.CertificateSpec{
+		Spec: certificatev1beta1.CertificateSpec{
 			CommonName:              "test.local",
 			SubjectAlternativeNames: []string{"test.local"},
 		},
@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	acmtypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+// CertificateReplacedFields returns the fields that differ between two specs
+// and can not be changed on an ACM certificate: a new ACM certificate is
+// requested when any of them changes.
+func CertificateReplacedFields(a, b *certificatev1beta1.CertificateSpec) []string {
+	var fields []string
+	if a.CommonName != b.CommonName {
+		fields = append(fields, "commonName")
+	}
+	if !sameNames(a.SubjectAlternativeNames, b.SubjectAlternativeNames) {
+		fields = append(fields, "subjectAlternativeNames")
+	}
+	if !sameIssuer(a.IssuerRef, b.IssuerRef) {
+		fields = append(fields, "issuerRef")
+	}
+	if a.Region != b.Region {
+		fields = append(fields, "region")
+	}
+	if a.ValidationMethod() != b.ValidationMethod() {
+		fields = append(fields, "validation.method")
+	}
+	if a.KeyAlgorithm() != b.KeyAlgorithm() {
+		fields = append(fields, "options.keyAlgorithm")
+	}
+	if transparencyLogging(a) != transparencyLogging(b) {
+		fields = append(fields, "options.certificateTransparencyLogging")
+	}
+	if exportEnabled(a) != exportEnabled(b) {
+		fields = append(fields, "export.enabled")
+	}
+	return fields
+}
+
+// issuedCertificateSpec returns the spec of an ACM certificate requested for a
+// Certificate. The issuer and region are the ones recorded when it was
+// requested, and the options the Certificate leaves to the ACM defaults are
+// taken from the Certificate so they are not compared.
+func issuedCertificateSpec(cert *certificatev1beta1.Certificate, detail *acmtypes.CertificateDetail) *certificatev1beta1.CertificateSpec {
+	spec := &certificatev1beta1.CertificateSpec{
+		CommonName:              aws.ToString(detail.DomainName),
+		SubjectAlternativeNames: detail.SubjectAlternativeNames,
+		IssuerRef:               cert.Status.RequestedIssuerRef,
+		Region:                  cert.Status.RequestedRegion,
+		Validation:              cert.Spec.Validation,
+		Options:                 &certificatev1beta1.CertificateOptions{},
+	}
+
+	// private certificates are not validated
+	if detail.Type != acmtypes.CertificateTypePrivate && len(detail.DomainValidationOptions) > 0 {
+		if method := detail.DomainValidationOptions[0].ValidationMethod; method != "" {
+			spec.Validation = &certificatev1beta1.CertificateValidation{Method: certificatev1beta1.CertificateValidationMethod(method)}
+		}
+	}
+	if cert.Spec.KeyAlgorithm() != "" {
+		spec.Options.KeyAlgorithm = certificatev1beta1.CertificateKeyAlgorithm(detail.KeyAlgorithm)
+	}
+	if logging := transparencyLogging(&cert.Spec); logging != "" {
+		if detail.Options != nil {
+			switch detail.Options.CertificateTransparencyLoggingPreference {
+			case acmtypes.CertificateTransparencyLoggingPreferenceEnabled:
+				logging = certificatev1beta1.CertificateTransparencyLoggingEnabled
+			case acmtypes.CertificateTransparencyLoggingPreferenceDisabled:
+				logging = certificatev1beta1.CertificateTransparencyLoggingDisabled
+			}
+		}
+		spec.Options.CertificateTransparencyLogging = logging
+	}
+	if detail.Options != nil && detail.Options.Export == acmtypes.CertificateExportEnabled {
+		spec.Export = &certificatev1beta1.CertificateExport{Enabled: true}
+	}
+
+	return spec
+}
+
+// requestedLocationChanged returns true when the issuer or the region of the
+// spec differs from the ones the ACM certificate was requested with, so it
+// can not be described with the ACM client of the spec. Certificates
+// requested before the location was recorded get the one of their spec while
+// it did not change.
+func requestedLocationChanged(cert *certificatev1beta1.Certificate) bool {
+	if cert.Status.RequestedIssuerRef == nil && cert.Status.RequestedRegion == "" &&
+		cert.Status.SpecHash == certificateSpecHash(&cert.Spec) {
+		recordRequestedLocation(cert)
+		return false
+	}
+	return !sameIssuer(cert.Status.RequestedIssuerRef, cert.Spec.IssuerRef) || cert.Status.RequestedRegion != cert.Spec.Region
+}
+
+// recordRequestedLocation records the issuer and the region of the spec as the
+// ones the ACM certificate was requested with.
+func recordRequestedLocation(cert *certificatev1beta1.Certificate) {
+	cert.Status.RequestedIssuerRef = cert.Spec.IssuerRef.DeepCopy()
+	cert.Status.RequestedRegion = cert.Spec.Region
+}
+
+// sameIssuer returns true when both references select the same issuer, the
+// kind defaulting to ACMIssuer.
+func sameIssuer(a, b *certificatev1beta1.IssuerReference) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	kind := func(ref *certificatev1beta1.IssuerReference) string {
+		if ref.Kind == "" {
+			return certificatev1beta1.IssuerKind
+		}
+		return ref.Kind
+	}
+	return a.Name == b.Name && kind(a) == kind(b)
+}
+
+// sameNames returns true when both lists hold the same names in any order.
+func sameNames(a, b []string) bool {
+	x := slices.Clone(a)
+	y := slices.Clone(b)
+	slices.Sort(x)
+	slices.Sort(y)
+	return slices.Equal(x, y)
+}
+
+func transparencyLogging(spec *certificatev1beta1.CertificateSpec) certificatev1beta1.CertificateTransparencyLogging {
+	if spec.Options == nil {
+		return ""
+	}
+	return spec.Options.CertificateTransparencyLogging
+}
+
+func exportEnabled(spec *certificatev1beta1.CertificateSpec) bool {
+	return spec.Export != nil && spec.Export.Enabled
+}
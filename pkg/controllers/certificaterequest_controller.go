@@ -0,0 +1,170 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"vdesjardins/acm-manager/pkg/controllers/external_api_clients"
+
+	apiutil "github.com/cert-manager/cert-manager/pkg/api/util"
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+// CertificateRequestReconciler is a cert-manager external issuer. It signs the
+// CertificateRequests referencing an ACMIssuer or ClusterACMIssuer with the
+// ACM Private CA of the issuer. The private key of a request is not available to
+// ACM, the certificates are therefore issued by the private CA directly and are
+// not listed in ACM.
+type CertificateRequestReconciler struct {
+	client.Client
+	Scheme  *runtime.Scheme
+	Clients external_api_clients.ClientFactory
+}
+
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificaterequests,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificaterequests/status,verbs=get;update;patch
+
+// Reconcile issues the certificate of an approved CertificateRequest.
+func (r *CertificateRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	cr := &cmapi.CertificateRequest{}
+	if err := r.Get(ctx, req.NamespacedName, cr); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !isACMIssuerReference(cr.Spec.IssuerRef) || isCertificateRequestDone(cr) {
+		return ctrl.Result{}, nil
+	}
+
+	if apiutil.CertificateRequestIsDenied(cr) {
+		return ctrl.Result{}, r.fail(ctx, cr, cmapi.CertificateRequestReasonDenied, "the certificate request was denied")
+	}
+	if !apiutil.CertificateRequestIsApproved(cr) {
+		log.V(1).Info("waiting for the certificate request to be approved")
+		return ctrl.Result{}, nil
+	}
+
+	issuer, err := getIssuer(ctx, r.Client, cr.Namespace, &certificatev1beta1.IssuerReference{
+		Name: cr.Spec.IssuerRef.Name,
+		Kind: cr.Spec.IssuerRef.Kind,
+	})
+	var issuerErr *IssuerNotReadyError
+	if errors.As(err, &issuerErr) {
+		return ctrl.Result{RequeueAfter: issuerRetryInterval}, r.pending(ctx, cr, issuerErr.Error())
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	spec := issuer.GetSpec()
+	if spec.PrivateCAARN == "" {
+		return ctrl.Result{}, r.fail(ctx, cr, cmapi.CertificateRequestReasonFailed,
+			fmt.Sprintf("%s %s has no private CA, ACM can only sign certificate requests with a private CA", cr.Spec.IssuerRef.Kind, cr.Spec.IssuerRef.Name))
+	}
+	if cr.Spec.IsCA {
+		return ctrl.Result{}, r.fail(ctx, cr, cmapi.CertificateRequestReasonFailed, "CA certificates can not be requested")
+	}
+
+	role, err := issuerRole(issuer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	duration := cmapi.DefaultCertificateDuration
+	if cr.Spec.Duration != nil {
+		duration = cr.Spec.Duration.Duration
+	}
+	signer := &privateCASigner{
+		Client: r.Client,
+		PCA:    r.Clients.PCA(spec.Region, role),
+		CAARN:  spec.PrivateCAARN,
+	}
+	cert, ca, err := signer.sign(ctx, cr, cr.Spec.Request, duration)
+	var csrErr *InvalidCSRError
+	if errors.As(err, &csrErr) {
+		return ctrl.Result{}, r.fail(ctx, cr, cmapi.CertificateRequestReasonFailed, csrErr.Error())
+	}
+	if err != nil {
+		return ctrl.Result{}, errors.Join(err, r.pending(ctx, cr, err.Error()))
+	}
+	if cert == nil {
+		log.V(1).Info("waiting for the private CA to issue the certificate")
+		return ctrl.Result{RequeueAfter: privateCAPollInterval}, r.pending(ctx, cr, "waiting for the private CA to issue the certificate")
+	}
+
+	cr.Status.Certificate, cr.Status.CA = cert, ca
+	apiutil.SetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady, cmmeta.ConditionTrue,
+		cmapi.CertificateRequestReasonIssued, fmt.Sprintf("certificate %s issued", cr.Annotations[IssuedCertificateARNAnnotation]))
+	if err := r.Status().Update(ctx, cr); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to update certificate request status: %w", err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// pending reports in the Ready condition why the certificate is not issued yet.
+func (r *CertificateRequestReconciler) pending(ctx context.Context, cr *cmapi.CertificateRequest, message string) error {
+	apiutil.SetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady, cmmeta.ConditionFalse,
+		cmapi.CertificateRequestReasonPending, message)
+	if err := r.Status().Update(ctx, cr); err != nil {
+		return fmt.Errorf("unable to update certificate request status: %w", err)
+	}
+	return nil
+}
+
+// fail marks a CertificateRequest as failed, cert-manager creates a new one
+// to retry.
+func (r *CertificateRequestReconciler) fail(ctx context.Context, cr *cmapi.CertificateRequest, reason, message string) error {
+	now := metav1.Now()
+	cr.Status.FailureTime = &now
+	apiutil.SetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady, cmmeta.ConditionFalse, reason, message)
+	if err := r.Status().Update(ctx, cr); err != nil {
+		return fmt.Errorf("unable to update certificate request status: %w", err)
+	}
+	return nil
+}
+
+// isACMIssuerReference returns true when a cert-manager issuer reference
+// selects an ACMIssuer or ClusterACMIssuer.
+func isACMIssuerReference(ref cmmeta.IssuerReference) bool {
+	return ref.Group == certificatev1beta1.SchemeGroupVersion.Group &&
+		(ref.Kind == certificatev1beta1.IssuerKind || ref.Kind == certificatev1beta1.ClusterIssuerKind)
+}
+
+// isCertificateRequestDone returns true when the CertificateRequest was issued
+// or failed.
+func isCertificateRequestDone(cr *cmapi.CertificateRequest) bool {
+	ready := apiutil.GetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady)
+	return ready != nil && (ready.Status == cmmeta.ConditionTrue ||
+		ready.Reason == cmapi.CertificateRequestReasonFailed || ready.Reason == cmapi.CertificateRequestReasonDenied)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CertificateRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&cmapi.CertificateRequest{}).
+		Complete(r)
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acmpca"
+	pcatypes "github.com/aws/aws-sdk-go-v2/service/acmpca/types"
+	apiutil "github.com/cert-manager/cert-manager/pkg/api/util"
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+const (
+	testLeafPEM         = "-----BEGIN CERTIFICATE-----\nbGVhZg==\n-----END CERTIFICATE-----\n"
+	testIntermediatePEM = "-----BEGIN CERTIFICATE-----\naW50ZXJtZWRpYXRl\n-----END CERTIFICATE-----\n"
+	testRootPEM         = "-----BEGIN CERTIFICATE-----\ncm9vdA==\n-----END CERTIFICATE-----\n"
+)
+
+// pcaClientMock issues a certificate that is available on the second get.
+type pcaClientMock struct {
+	issued int
+	gets   int
+	input  *acmpca.IssueCertificateInput
+
+	// certificate of the CA
+	caCertificate string
+}
+
+func (p *pcaClientMock) DescribeCertificateAuthority(ctx context.Context, params *acmpca.DescribeCertificateAuthorityInput, optFns ...func(*acmpca.Options)) (*acmpca.DescribeCertificateAuthorityOutput, error) {
+	return &acmpca.DescribeCertificateAuthorityOutput{
+		CertificateAuthority: &pcatypes.CertificateAuthority{
+			CertificateAuthorityConfiguration: &pcatypes.CertificateAuthorityConfiguration{
+				SigningAlgorithm: pcatypes.SigningAlgorithmSha256withrsa,
+			},
+		},
+	}, nil
+}
+
+func (p *pcaClientMock) IssueCertificate(ctx context.Context, params *acmpca.IssueCertificateInput, optFns ...func(*acmpca.Options)) (*acmpca.IssueCertificateOutput, error) {
+	p.issued++
+	p.input = params
+	return &acmpca.IssueCertificateOutput{CertificateArn: aws.String(*params.CertificateAuthorityArn + "/certificate/test")}, nil
+}
+
+func (p *pcaClientMock) GetCertificate(ctx context.Context, params *acmpca.GetCertificateInput, optFns ...func(*acmpca.Options)) (*acmpca.GetCertificateOutput, error) {
+	p.gets++
+	if p.gets == 1 {
+		return nil, &pcatypes.RequestInProgressException{}
+	}
+	return &acmpca.GetCertificateOutput{
+		Certificate:      aws.String(testLeafPEM),
+		CertificateChain: aws.String(testIntermediatePEM + testRootPEM),
+	}, nil
+}
+
+func (p *pcaClientMock) GetCertificateAuthorityCertificate(ctx context.Context, params *acmpca.GetCertificateAuthorityCertificateInput, optFns ...func(*acmpca.Options)) (*acmpca.GetCertificateAuthorityCertificateOutput, error) {
+	return &acmpca.GetCertificateAuthorityCertificateOutput{Certificate: aws.String(p.caCertificate)}, nil
+}
+
+var _ = Describe("cert-manager external issuer", func() {
+	newIssuer := func(name, privateCAARN string) *certificatev1beta1.ClusterACMIssuer {
+		return &certificatev1beta1.ClusterACMIssuer{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       certificatev1beta1.ACMIssuerSpec{PrivateCAARN: privateCAARN},
+			Status: certificatev1beta1.ACMIssuerStatus{
+				Conditions: []metav1.Condition{{
+					Type:   certificatev1beta1.IssuerConditionReady,
+					Status: metav1.ConditionTrue,
+					Reason: certificatev1beta1.IssuerReasonVerified,
+				}},
+			},
+		}
+	}
+
+	newRequest := func(issuer string, approved bool) *cmapi.CertificateRequest {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+			Subject:  pkix.Name{CommonName: "app.example.com"},
+			DNSNames: []string{"app.example.com"},
+		}, key)
+		Expect(err).NotTo(HaveOccurred())
+
+		cr := &cmapi.CertificateRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "4b1f2c3d-0000-0000-0000-000000000000"},
+			Spec: cmapi.CertificateRequestSpec{
+				Request: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}),
+				IssuerRef: cmmeta.IssuerReference{
+					Group: certificatev1beta1.SchemeGroupVersion.Group,
+					Kind:  certificatev1beta1.ClusterIssuerKind,
+					Name:  issuer,
+				},
+			},
+		}
+		if approved {
+			apiutil.SetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionApproved, cmmeta.ConditionTrue, "Approved", "approved")
+		}
+		return cr
+	}
+
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test", Namespace: "default"}}
+	caARN := "arn:aws:acm-pca:us-east-1:123456789012:certificate-authority/test"
+
+	It("Should sign approved requests with the private CA of the issuer", func() {
+		ctx := context.Background()
+		pca := &pcaClientMock{}
+		r := &CertificateRequestReconciler{
+			Client:  newFakeClient(newIssuer("private", caARN), newRequest("private", true)),
+			Clients: &clientFactoryMock{pca: pca},
+		}
+
+		By("waiting for the private CA to issue the certificate")
+		result, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).NotTo(BeZero())
+
+		cr := &cmapi.CertificateRequest{}
+		Expect(r.Get(ctx, request.NamespacedName, cr)).To(Succeed())
+		Expect(cr.Annotations).To(HaveKeyWithValue(IssuedCertificateARNAnnotation, caARN+"/certificate/test"))
+		Expect(apiutil.CertificateRequestReadyReason(cr)).To(Equal(cmapi.CertificateRequestReasonPending))
+
+		By("filling the certificate and its CA once issued")
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pca.issued).To(Equal(1))
+
+		Expect(r.Get(ctx, request.NamespacedName, cr)).To(Succeed())
+		Expect(apiutil.CertificateRequestReadyReason(cr)).To(Equal(cmapi.CertificateRequestReasonIssued))
+		Expect(string(cr.Status.Certificate)).To(Equal(testLeafPEM + testIntermediatePEM))
+		Expect(string(cr.Status.CA)).To(Equal(testRootPEM))
+	})
+
+	It("Should only sign approved requests of issuers with a private CA", func() {
+		ctx := context.Background()
+		pca := &pcaClientMock{}
+		c := newFakeClient(newIssuer("private", caARN), newIssuer("public", ""), newRequest("private", false))
+		r := &CertificateRequestReconciler{Client: c, Clients: &clientFactoryMock{pca: pca}}
+
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pca.issued).To(BeZero())
+
+		Expect(c.Delete(ctx, &cmapi.CertificateRequest{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}})).To(Succeed())
+		Expect(c.Create(ctx, newRequest("public", true))).To(Succeed())
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pca.issued).To(BeZero())
+
+		cr := &cmapi.CertificateRequest{}
+		Expect(r.Get(ctx, request.NamespacedName, cr)).To(Succeed())
+		Expect(apiutil.CertificateRequestReadyReason(cr)).To(Equal(cmapi.CertificateRequestReasonFailed))
+		Expect(cr.Status.FailureTime).NotTo(BeNil())
+	})
+})
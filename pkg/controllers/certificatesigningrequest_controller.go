@@ -0,0 +1,175 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"vdesjardins/acm-manager/pkg/controllers/external_api_clients"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+const (
+	// PrivateCASignerName is the signer name of the CertificateSigningRequests
+	// signed by the ACM Private CA
+	PrivateCASignerName = "acm-manager.io/private-ca"
+
+	// csrDefaultDuration is the validity of the certificates of the
+	// CertificateSigningRequests without expirationSeconds, the default of the
+	// Kubernetes signers
+	csrDefaultDuration = time.Hour * 24 * 365
+)
+
+// CertificateSigningRequestReconciler signs the approved CertificateSigningRequests
+// of the acm-manager.io/private-ca signer with the ACM Private CA of a
+// ClusterACMIssuer. Like the cert-manager issuer, the certificates are issued by
+// the private CA directly since ACM does not have their private key.
+type CertificateSigningRequestReconciler struct {
+	client.Client
+	Scheme  *runtime.Scheme
+	Clients external_api_clients.ClientFactory
+
+	// IssuerName is the ClusterACMIssuer whose private CA signs the requests
+	IssuerName string
+}
+
+//+kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=certificates.k8s.io,resources=signers,resourceNames=acm-manager.io/private-ca,verbs=sign
+
+// Reconcile signs an approved CertificateSigningRequest.
+func (r *CertificateSigningRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	csr := &certificatesv1.CertificateSigningRequest{}
+	if err := r.Get(ctx, req.NamespacedName, csr); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if csr.Spec.SignerName != PrivateCASignerName || len(csr.Status.Certificate) > 0 {
+		return ctrl.Result{}, nil
+	}
+	if hasCSRCondition(csr, certificatesv1.CertificateFailed) || hasCSRCondition(csr, certificatesv1.CertificateDenied) {
+		return ctrl.Result{}, nil
+	}
+	if !hasCSRCondition(csr, certificatesv1.CertificateApproved) {
+		log.V(1).Info("waiting for the certificate signing request to be approved")
+		return ctrl.Result{}, nil
+	}
+
+	issuer, err := getIssuer(ctx, r.Client, "", &certificatev1beta1.IssuerReference{
+		Name: r.IssuerName,
+		Kind: certificatev1beta1.ClusterIssuerKind,
+	})
+	var issuerErr *IssuerNotReadyError
+	if errors.As(err, &issuerErr) {
+		log.Info("waiting for the issuer of the signer", "reason", issuerErr.Error())
+		return ctrl.Result{RequeueAfter: issuerRetryInterval}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	spec := issuer.GetSpec()
+	if spec.PrivateCAARN == "" {
+		return ctrl.Result{}, r.fail(ctx, csr, "IssuerWithoutPrivateCA",
+			fmt.Sprintf("%s %s has no private CA", certificatev1beta1.ClusterIssuerKind, r.IssuerName))
+	}
+
+	role, err := issuerRole(issuer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	duration := csrDefaultDuration
+	if csr.Spec.ExpirationSeconds != nil {
+		duration = time.Duration(*csr.Spec.ExpirationSeconds) * time.Second
+	}
+	signer := &privateCASigner{
+		Client: r.Client,
+		PCA:    r.Clients.PCA(spec.Region, role),
+		CAARN:  spec.PrivateCAARN,
+	}
+	cert, _, err := signer.sign(ctx, csr, csr.Spec.Request, duration)
+	var csrErr *InvalidCSRError
+	if errors.As(err, &csrErr) {
+		return ctrl.Result{}, r.fail(ctx, csr, "InvalidRequest", csrErr.Error())
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if cert == nil {
+		log.V(1).Info("waiting for the private CA to issue the certificate")
+		return ctrl.Result{RequeueAfter: privateCAPollInterval}, nil
+	}
+
+	// the root CA is distributed out of band, like the cluster CA
+	csr.Status.Certificate = cert
+	if err := r.Status().Update(ctx, csr); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to update certificate signing request status: %w", err)
+	}
+	log.Info("certificate signing request signed", "certificateArn", csr.Annotations[IssuedCertificateARNAnnotation])
+
+	return ctrl.Result{}, nil
+}
+
+// fail adds the Failed condition to a CertificateSigningRequest, it is not
+// signed again.
+func (r *CertificateSigningRequestReconciler) fail(ctx context.Context, csr *certificatesv1.CertificateSigningRequest, reason, message string) error {
+	now := metav1.Now()
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type:               certificatesv1.CertificateFailed,
+		Status:             core.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		LastUpdateTime:     now,
+		LastTransitionTime: now,
+	})
+	if err := r.Status().Update(ctx, csr); err != nil {
+		return fmt.Errorf("unable to update certificate signing request status: %w", err)
+	}
+	return nil
+}
+
+func hasCSRCondition(csr *certificatesv1.CertificateSigningRequest, conditionType certificatesv1.RequestConditionType) bool {
+	for _, c := range csr.Status.Conditions {
+		if c.Type == conditionType && c.Status == core.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CertificateSigningRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&certificatesv1.CertificateSigningRequest{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.(*certificatesv1.CertificateSigningRequest).Spec.SignerName == PrivateCASignerName
+		}))).
+		Complete(r)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+var _ = Describe("Private CA signer", func() {
+	caARN := "arn:aws:acm-pca:us-east-1:123456789012:certificate-authority/test"
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "workload"}}
+
+	newSignerClient := func(objs ...client.Object) client.Client {
+		issuer := &certificatev1beta1.ClusterACMIssuer{
+			ObjectMeta: metav1.ObjectMeta{Name: "private"},
+			Spec:       certificatev1beta1.ACMIssuerSpec{PrivateCAARN: caARN},
+			Status: certificatev1beta1.ACMIssuerStatus{
+				Conditions: []metav1.Condition{{
+					Type:   certificatev1beta1.IssuerConditionReady,
+					Status: metav1.ConditionTrue,
+					Reason: certificatev1beta1.IssuerReasonVerified,
+				}},
+			},
+		}
+		return newFakeClient(append(objs, issuer)...)
+	}
+
+	newCSR := func(signerName string, approved bool) *certificatesv1.CertificateSigningRequest {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+			Subject: pkix.Name{CommonName: "workload.default.svc"},
+		}, key)
+		Expect(err).NotTo(HaveOccurred())
+
+		csr := &certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "workload", UID: "9a8b7c6d-0000-0000-0000-000000000000"},
+			Spec: certificatesv1.CertificateSigningRequestSpec{
+				Request:           pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}),
+				SignerName:        signerName,
+				ExpirationSeconds: ptr.To[int32](3600),
+				Usages:            []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageClientAuth},
+			},
+		}
+		if approved {
+			csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{{
+				Type:   certificatesv1.CertificateApproved,
+				Status: core.ConditionTrue,
+			}}
+		}
+		return csr
+	}
+
+	It("Should sign approved requests for their expiration", func() {
+		ctx := context.Background()
+		pca := &pcaClientMock{}
+		r := &CertificateSigningRequestReconciler{
+			Client:     newSignerClient(newCSR(PrivateCASignerName, true)),
+			Clients:    &clientFactoryMock{pca: pca},
+			IssuerName: "private",
+		}
+
+		result, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(privateCAPollInterval))
+		Expect(*pca.input.CertificateAuthorityArn).To(Equal(caARN))
+		Expect(*pca.input.Validity.Value).To(BeNumerically("~", time.Now().Add(time.Hour).Unix(), 5))
+
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pca.issued).To(Equal(1))
+
+		csr := &certificatesv1.CertificateSigningRequest{}
+		Expect(r.Get(ctx, request.NamespacedName, csr)).To(Succeed())
+		Expect(string(csr.Status.Certificate)).To(Equal(testLeafPEM + testIntermediatePEM))
+	})
+
+	It("Should ignore other signers and requests that are not approved", func() {
+		ctx := context.Background()
+		pca := &pcaClientMock{}
+
+		for _, csr := range []*certificatesv1.CertificateSigningRequest{
+			newCSR("kubernetes.io/kube-apiserver-client", true),
+			newCSR(PrivateCASignerName, false),
+		} {
+			r := &CertificateSigningRequestReconciler{
+				Client:     newSignerClient(csr),
+				Clients:    &clientFactoryMock{pca: pca},
+				IssuerName: "private",
+			}
+			_, err := r.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(pca.issued).To(BeZero())
+	})
+})
@@ -16,6 +16,8 @@ type AcmAWSAPI interface {
 	DeleteCertificate(ctx context.Context, params *acm.DeleteCertificateInput, optFns ...func(*acm.Options)) (*acm.DeleteCertificateOutput, error)
 	ListCertificates(ctx context.Context, params *acm.ListCertificatesInput, optFns ...func(*acm.Options)) (*acm.ListCertificatesOutput, error)
 	ListTagsForCertificate(ctx context.Context, params *acm.ListTagsForCertificateInput, optFns ...func(*acm.Options)) (*acm.ListTagsForCertificateOutput, error)
+	AddTagsToCertificate(ctx context.Context, params *acm.AddTagsToCertificateInput, optFns ...func(*acm.Options)) (*acm.AddTagsToCertificateOutput, error)
+	RemoveTagsFromCertificate(ctx context.Context, params *acm.RemoveTagsFromCertificateInput, optFns ...func(*acm.Options)) (*acm.RemoveTagsFromCertificateOutput, error)
 }
 
 var NewAcmClient = func(service *acm.Client) AcmAWSAPI {
@@ -35,9 +37,17 @@ func (a *acmClient) DeleteCertificate(ctx context.Context, params *acm.DeleteCer
 }
 
 func (a *acmClient) ListCertificates(ctx context.Context, params *acm.ListCertificatesInput, optFns ...func(*acm.Options)) (*acm.ListCertificatesOutput, error) {
-	return a.svc.ListCertificates(ctx, params)
+	return a.svc.ListCertificates(ctx, params, optFns...)
 }
 
 func (a *acmClient) ListTagsForCertificate(ctx context.Context, params *acm.ListTagsForCertificateInput, optFns ...func(*acm.Options)) (*acm.ListTagsForCertificateOutput, error) {
-	return a.svc.ListTagsForCertificate(ctx, params)
+	return a.svc.ListTagsForCertificate(ctx, params, optFns...)
+}
+
+func (a *acmClient) AddTagsToCertificate(ctx context.Context, params *acm.AddTagsToCertificateInput, optFns ...func(*acm.Options)) (*acm.AddTagsToCertificateOutput, error) {
+	return a.svc.AddTagsToCertificate(ctx, params, optFns...)
+}
+
+func (a *acmClient) RemoveTagsFromCertificate(ctx context.Context, params *acm.RemoveTagsFromCertificateInput, optFns ...func(*acm.Options)) (*acm.RemoveTagsFromCertificateOutput, error) {
+	return a.svc.RemoveTagsFromCertificate(ctx, params, optFns...)
 }
@@ -0,0 +1,84 @@
+package external_api_clients
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acmpca"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// ClientFactory returns AWS clients for a region and an optional IAM role to
+// assume. An empty region or role uses the ones of the base configuration.
+type ClientFactory interface {
+	ACM(region string, role Role) AcmAWSAPI
+	STS(region string, role Role) StsAWSAPI
+	PCA(region string, role Role) PcaAWSAPI
+}
+
+// Role is an IAM role to assume. The external ID, when set, is passed to
+// AssumeRole so the trust policy of the role can restrict who assumes it.
+type Role struct {
+	ARN        string
+	ExternalID string
+}
+
+type clientKey struct {
+	region string
+	role   Role
+}
+
+type clientFactory struct {
+	base aws.Config
+
+	mu      sync.Mutex
+	configs map[clientKey]aws.Config
+}
+
+var NewClientFactory = func(base aws.Config) ClientFactory {
+	return &clientFactory{base: base, configs: map[clientKey]aws.Config{}}
+}
+
+func (f *clientFactory) ACM(region string, role Role) AcmAWSAPI {
+	return NewAcmClient(acm.NewFromConfig(f.config(region, role)))
+}
+
+func (f *clientFactory) STS(region string, role Role) StsAWSAPI {
+	return NewStsClient(sts.NewFromConfig(f.config(region, role)))
+}
+
+// PCA returns an ACM Private CA client using the credentials of a region and
+// role.
+func (f *clientFactory) PCA(region string, role Role) PcaAWSAPI {
+	return NewPcaClient(acmpca.NewFromConfig(f.config(region, role)))
+}
+
+// config returns the configuration of a region and role. It is cached so the
+// assumed role credentials are shared and only refreshed when they expire.
+func (f *clientFactory) config(region string, role Role) aws.Config {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := clientKey{region: region, role: role}
+	if cfg, ok := f.configs[key]; ok {
+		return cfg
+	}
+
+	cfg := f.base.Copy()
+	if region != "" {
+		cfg.Region = region
+	}
+	if role.ARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), role.ARN, func(o *stscreds.AssumeRoleOptions) {
+			if role.ExternalID != "" {
+				o.ExternalID = aws.String(role.ExternalID)
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+	f.configs[key] = cfg
+
+	return cfg
+}
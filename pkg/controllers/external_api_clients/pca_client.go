@@ -0,0 +1,38 @@
+package external_api_clients
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/acmpca"
+)
+
+type pcaClient struct {
+	svc *acmpca.Client
+}
+
+type PcaAWSAPI interface {
+	DescribeCertificateAuthority(ctx context.Context, params *acmpca.DescribeCertificateAuthorityInput, optFns ...func(*acmpca.Options)) (*acmpca.DescribeCertificateAuthorityOutput, error)
+	IssueCertificate(ctx context.Context, params *acmpca.IssueCertificateInput, optFns ...func(*acmpca.Options)) (*acmpca.IssueCertificateOutput, error)
+	GetCertificate(ctx context.Context, params *acmpca.GetCertificateInput, optFns ...func(*acmpca.Options)) (*acmpca.GetCertificateOutput, error)
+	GetCertificateAuthorityCertificate(ctx context.Context, params *acmpca.GetCertificateAuthorityCertificateInput, optFns ...func(*acmpca.Options)) (*acmpca.GetCertificateAuthorityCertificateOutput, error)
+}
+
+var NewPcaClient = func(service *acmpca.Client) PcaAWSAPI {
+	return &pcaClient{svc: service}
+}
+
+func (p *pcaClient) DescribeCertificateAuthority(ctx context.Context, params *acmpca.DescribeCertificateAuthorityInput, optFns ...func(*acmpca.Options)) (*acmpca.DescribeCertificateAuthorityOutput, error) {
+	return p.svc.DescribeCertificateAuthority(ctx, params, optFns...)
+}
+
+func (p *pcaClient) IssueCertificate(ctx context.Context, params *acmpca.IssueCertificateInput, optFns ...func(*acmpca.Options)) (*acmpca.IssueCertificateOutput, error) {
+	return p.svc.IssueCertificate(ctx, params, optFns...)
+}
+
+func (p *pcaClient) GetCertificate(ctx context.Context, params *acmpca.GetCertificateInput, optFns ...func(*acmpca.Options)) (*acmpca.GetCertificateOutput, error) {
+	return p.svc.GetCertificate(ctx, params, optFns...)
+}
+
+func (p *pcaClient) GetCertificateAuthorityCertificate(ctx context.Context, params *acmpca.GetCertificateAuthorityCertificateInput, optFns ...func(*acmpca.Options)) (*acmpca.GetCertificateAuthorityCertificateOutput, error) {
+	return p.svc.GetCertificateAuthorityCertificate(ctx, params, optFns...)
+}
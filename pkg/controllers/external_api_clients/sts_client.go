@@ -0,0 +1,23 @@
+package external_api_clients
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+type stsClient struct {
+	svc *sts.Client
+}
+
+type StsAWSAPI interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+var NewStsClient = func(service *sts.Client) StsAWSAPI {
+	return &stsClient{svc: service}
+}
+
+func (s *stsClient) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	return s.svc.GetCallerIdentity(ctx, params, optFns...)
+}
@@ -18,44 +18,156 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
 	"slices"
 	"strings"
+	"text/template"
 	"time"
 
-	certificatev1alpha1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1alpha1"
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
 	certificateclient "vdesjardins/acm-manager/pkg/client/versioned"
+	"vdesjardins/acm-manager/pkg/policy"
 
+	core "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	networkingv1ac "k8s.io/client-go/applyconfigurations/networking/v1"
-	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
-	IngressClassValue        = "alb"
-	IngressSchemeKey         = "alb.ingress.kubernetes.io/scheme"
-	IngressSchemeValue       = "internet-facing"
-	IngressCertificateArnKey = "alb.ingress.kubernetes.io/certificate-arn"
+	// IngressClassValue is the legacy ingress class of the AWS Load Balancer
+	// Controller, matched when no IngressClass of this name exists
+	IngressClassValue         = "alb"
+	IngressClassAnnotationKey = "kubernetes.io/ingress.class"
+	IngressSchemeKey          = "alb.ingress.kubernetes.io/scheme"
+	IngressSchemeValue        = "internet-facing"
+	IngressCertificateArnKey  = "alb.ingress.kubernetes.io/certificate-arn"
+
+	// IngressManagedCertificateArnsKey holds the comma separated ARNs that
+	// acm-manager added to the certificate ARN annotation, the other ones are
+	// left untouched
+	IngressManagedCertificateArnsKey = "acm-manager.io/managed-certificate-arns"
 
 	ACMManagerCreateCertificateKey = "acm-manager.io/enable"
+	// IngressCertificateClassKey names the CertificateClass of the generated Certificate
+	IngressCertificateClassKey = "acm-manager.io/certificate-class"
+	// IngressHostSourceKey selects where the hosts of the generated Certificate come from
+	IngressHostSourceKey = "acm-manager.io/host-source"
+	// IngressIncludeHostsKey holds comma separated patterns, only the matching hosts are kept
+	IngressIncludeHostsKey = "acm-manager.io/include-hosts"
+	// IngressExcludeHostsKey holds comma separated patterns of the hosts left out
+	IngressExcludeHostsKey = "acm-manager.io/exclude-hosts"
+	// IngressCertificateRefKey references the <namespace>/<name> Certificate
+	// used by an Ingress instead of a generated one
+	IngressCertificateRefKey = "acm-manager.io/certificate-ref"
+	// IngressWildcardDomainsKey holds comma separated parent domains, the hosts
+	// directly below them are collapsed into *.<domain>
+	IngressWildcardDomainsKey = "acm-manager.io/wildcard-domains"
+)
+
+const (
+	IngressEventOptedOut                 = "OptedOut"
+	IngressEventInvalidCertificateRef    = "InvalidCertificateRef"
+	IngressEventInvalidCertificateName   = "InvalidCertificateName"
+	IngressEventCertificateNameCollision = "CertificateNameCollision"
+)
+
+const (
+	// IngressConditionsKey holds the JSON conditions of the certificate
+	// management of an Ingress
+	IngressConditionsKey = "acm-manager.io/conditions"
+
+	// IngressConditionCertificateNameAvailable indicates that the generated
+	// Certificates are not colliding with Certificates not owned by the Ingress
+	IngressConditionCertificateNameAvailable = "CertificateNameAvailable"
+
+	// IngressConditionDomainsAllowed indicates that the DomainPolicies of the
+	// namespace of an Ingress of a group allow all its hosts
+	IngressConditionDomainsAllowed = "DomainsAllowed"
+
+	// IngressConditionGroupOptionsApplied indicates that the certificate class
+	// and options of an Ingress of a group are the ones of the group Certificates
+	IngressConditionGroupOptionsApplied = "GroupCertificateOptionsApplied"
+
+	IngressReasonNameAvailable        = "Available"
+	IngressReasonNameCollision        = "NameCollision"
+	IngressReasonDomainsAllowed       = "Allowed"
+	IngressReasonDomainsDenied        = "DeniedByPolicy"
+	IngressReasonGroupOptionsApplied  = "Applied"
+	IngressReasonGroupOptionsConflict = "Conflict"
+	IngressReasonGroupOptionsInvalid  = "InvalidOptions"
+)
+
+// ingressNameCollisionRetryInterval is the interval at which the name of a
+// colliding Certificate is checked again.
+const ingressNameCollisionRetryInterval = time.Minute
+
+const (
+	// HostSourceTLS takes the hosts of the TLS entries
+	HostSourceTLS = "tls"
+	// HostSourceRules takes the hosts of the rules
+	HostSourceRules = "rules"
+	// HostSourceAll takes the hosts of both the TLS entries and the rules
+	HostSourceAll = "all"
 )
 
 var IngressAutoDetect = true
 
+// IngressHostSource is the host source of the Ingresses without the host
+// source annotation.
+var IngressHostSource = HostSourceTLS
+
+// IngressMaxCertificateHosts is the maximum number of hosts of a Certificate
+// generated for an Ingress, the default ACM quota of names per certificate.
+// The hosts of an Ingress are split across several Certificates beyond it.
+var IngressMaxCertificateHosts = 10
+
+// IngressCertificateNameTemplate is the template of the name of the first
+// Certificate generated for an Ingress, executed with the Ingress metadata.
+// The following ones are suffixed by their number.
+var IngressCertificateNameTemplate = template.Must(ParseIngressCertificateNameTemplate("{{ .Name }}"))
+
+// IngressWildcardDomains are the parent domains whose hosts are collapsed into
+// a wildcard name for the Ingresses without the wildcard domains annotation.
+var IngressWildcardDomains []string
+
+// IngressClassControllers are the controllers of the IngressClasses whose
+// Ingresses are auto detected.
+var IngressClassControllers = []string{"ingress.k8s.aws/alb"}
+
 // IngressReconciler reconciles a Ingress object
 type IngressReconciler struct {
 	client.Client
-	clientset  *kubernetes.Clientset
 	certClient certificateclient.Interface
+	recorder   record.EventRecorder
 	Scheme     *runtime.Scheme
+
+	// Region of the controller credentials, the one of the Certificates
+	// without region
+	Region string
+	// APIReader reads the Certificates of a name collision from the API
+	// server, the cache may not have them yet. The Client is used when not set
+	APIReader client.Reader
 }
 
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingressclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=acm-manager.io,resources=certificateshares,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -81,91 +193,734 @@ func (r *IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	// check for annotation that enable/disable auto cert creation
-	if !isIngressShouldCreateCert(ingress) {
+	shouldCreate, err := IsIngressShouldCreateCert(ctx, r.Client, ingress)
+	if err != nil {
+		log.Error(err, "unable to resolve ingress class")
+		return ctrl.Result{}, err
+	}
+	if !shouldCreate {
 		log.Info("ingress does not meet annotation criteria. skipping certificate generation")
+		if err := r.optOut(ctx, ingress); err != nil {
+			log.Error(err, "unable to clean up certificates of ingress")
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{}, nil
 	}
 
-	cert := &certificatev1alpha1.Certificate{}
-	newCert := false
-	if err := r.Get(ctx, req.NamespacedName, cert); err != nil {
-		if apierrors.IsNotFound(err) {
-			newCert = true
-			cert.Name = req.Name
-			cert.Namespace = req.Namespace
-		} else {
-			log.Error(err, "error loading certificate for ingress")
+	if ref := ingress.GetAnnotations()[IngressCertificateRefKey]; ref != "" {
+		return r.reconcileCertificateRef(ctx, ingress, ref)
+	}
+
+	if ingressGroupName(ingress) != "" && IngressGroupNamespace != "" {
+		// the certificates of the group are managed by the IngressGroupReconciler
+		if err := r.deleteReplacedCertificates(ctx, ingress); err != nil {
+			log.Error(err, "unable to delete certificates replaced by the ingress group ones")
 			return ctrl.Result{}, err
 		}
-	} else {
-		if len(cert.GetOwnerReferences()) == 0 ||
-			cert.GetOwnerReferences()[0].Kind != "Ingress" ||
-			cert.GetOwnerReferences()[0].Name != req.Name {
-			log.Info("owner reference does not match. skipping reconciliation")
-			return ctrl.Result{}, nil
-		}
+		return ctrl.Result{}, nil
 	}
 
-	hostMap := map[string]bool{}
-	for _, t := range ingress.Spec.TLS {
-		if t.SecretName != "" {
-			continue
-		}
-		for _, h := range t.Hosts {
-			hostMap[h] = true
-		}
+	baseName, err := IngressCertificateName(ingress)
+	if err != nil {
+		log.Error(err, "invalid certificate name")
+		r.recorder.Event(ingress, core.EventTypeWarning, IngressEventInvalidCertificateName, err.Error())
+		return ctrl.Result{}, nil
 	}
-	hosts := make([]string, 0, len(hostMap))
-	for k := range hostMap {
-		hosts = append(hosts, k)
+
+	options, err := parseIngressCertificateOptions(ingress)
+	if err != nil {
+		// fixed by a change of the ingress annotations
+		log.Info("invalid certificate options", "reason", err.Error())
+		r.recorder.Event(ingress, core.EventTypeWarning, IngressEventInvalidCertificateOptions, err.Error())
+		return ctrl.Result{}, nil
 	}
 
+	hosts := IngressHosts(ingress)
 	if len(hosts) == 0 {
 		log.Info("no host declared in ingress's resource. skipping certificate generation")
 		return ctrl.Result{}, nil
 	}
 
-	slices.Sort(hosts)
+	if len(ingressWildcardDomains(ingress)) > 0 {
+		reused, err := r.reusableCertificate(ctx, ingress, hosts, options.region)
+		if err != nil {
+			log.Error(err, "unable to list reusable certificates")
+			return ctrl.Result{}, err
+		}
+		if reused != nil {
+			if err := r.setCertificateArns(ctx, ingress, []string{reused.Status.CertificateArn}); err != nil {
+				log.Error(err, "unable to update ingress with certificate arn")
+				return ctrl.Result{}, err
+			}
+			if err := r.deleteReplacedCertificates(ctx, ingress); err != nil {
+				log.Error(err, "unable to delete certificates replaced by the reused one")
+				return ctrl.Result{}, err
+			}
+			log.V(1).Info("certificate reused", "certificate", reused.Namespace+"/"+reused.Name)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	owned, err := ownedCertificates(ctx, r.Client, ingress)
+	if err != nil {
+		log.Error(err, "unable to list certificates of ingress")
+		return ctrl.Result{}, err
+	}
+	previous := make([][]string, len(owned))
+	for i := range owned {
+		previous[i] = owned[i].Spec.SubjectAlternativeNames
+	}
+	groups := partitionHosts(hosts, previous, IngressMaxCertificateHosts)
 
-	cert.Spec.CommonName = hosts[0]
-	cert.Spec.SubjectAlternativeNames = hosts
-	ctrl.SetControllerReference(metav1.Object(ingress), cert, r.Scheme)
+	var arns []string
+	issued := true
+	for i, group := range groups {
+		newCert := i >= len(owned)
+		cert := &certificatev1beta1.Certificate{}
+		if newCert {
+			cert.Name = partitionCertificateName(baseName, owned)
+			cert.Namespace = ingress.Namespace
+		} else {
+			cert = &owned[i]
+		}
 
-	if newCert {
-		if err := r.Create(ctx, cert); err != nil {
-			log.Error(err, "unable to create certificate for ingress")
-			return ctrl.Result{}, err
+		if len(group) == 0 {
+			// all the hosts of the certificate were removed from the ingress
+			if err := r.Delete(ctx, cert); client.IgnoreNotFound(err) != nil {
+				log.Error(err, "unable to delete certificate of ingress", "certificate", cert.Name)
+				return ctrl.Result{}, err
+			}
+			log.Info("certificate without hosts deleted", "certificate", cert.Name)
+			continue
 		}
-		log.Info("certificate created")
-	} else {
-		if err := updateCertificate(ctx, r.certClient, cert); err != nil {
-			log.Error(err, "unable to update certificate for ingress")
+
+		cert.Spec.CommonName = group[0]
+		cert.Spec.SubjectAlternativeNames = group
+		cert.Spec.CertificateClassName = ingress.GetAnnotations()[IngressCertificateClassKey]
+		options.applyTo(&cert.Spec)
+		var alreadyOwned *controllerutil.AlreadyOwnedError
+		if err := ctrl.SetControllerReference(ingress, cert, r.Scheme); err != nil && !errors.As(err, &alreadyOwned) {
+			// an existing certificate controlled by another object stays owned by the ingress
+			log.Error(err, "unable to set owner of certificate", "certificate", cert.Name)
 			return ctrl.Result{}, err
 		}
-		log.Info("certificate updated from ingress info")
+
+		if newCert {
+			if err := r.Create(ctx, cert); err != nil {
+				if apierrors.IsAlreadyExists(err) {
+					return r.nameCollision(ctx, ingress, cert)
+				}
+				log.Error(err, "unable to create certificate for ingress", "certificate", cert.Name)
+				return ctrl.Result{}, err
+			}
+			owned = append(owned, *cert)
+			log.Info("certificate created", "certificate", cert.Name)
+		} else {
+			if err := updateCertificate(ctx, r.certClient, cert); err != nil {
+				log.Error(err, "unable to update certificate for ingress", "certificate", cert.Name)
+				return ctrl.Result{}, err
+			}
+			log.Info("certificate updated from ingress info", "certificate", cert.Name)
+		}
+
+		if cert.Status.CertificateArn == "" || cert.Status.Status != certificatev1beta1.CertificateStatusIssued {
+			issued = false
+		}
+		arns = append(arns, cert.Status.CertificateArn)
+	}
+
+	if err := r.clearNameCollision(ctx, ingress); err != nil {
+		log.Error(err, "unable to update ingress conditions")
+		return ctrl.Result{}, err
 	}
 
-	// update ingress with certificate ARN if available. if not requeue
-	if cert.Status.CertificateArn == "" || cert.Status.Status != certificatev1alpha1.CertificateStatusIssued {
+	// update ingress with certificate ARNs once all issued. if not requeue
+	if !issued {
 		return ctrl.Result{
 			RequeueAfter: time.Second * 10,
 		}, nil
 	}
-	if ingress.GetAnnotations()[IngressCertificateArnKey] != cert.Status.CertificateArn {
-		ingac := networkingv1ac.Ingress(ingress.GetName(), ingress.GetNamespace()).
-			WithAnnotations(map[string]string{IngressCertificateArnKey: cert.Status.CertificateArn})
+	if err := r.setCertificateArns(ctx, ingress, arns); err != nil {
+		log.Error(err, "unable to update ingress with certificate arn")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// setCertificateArns sets the ARNs managed by acm-manager on an Ingress, the
+// ones added by users being kept.
+func (r *IngressReconciler) setCertificateArns(ctx context.Context, ingress *networkingv1.Ingress, managed []string) error {
+	annotations, changed := certificateArnAnnotations(ingress, managed)
+	if !changed {
+		return nil
+	}
+	return applyCertificateArnAnnotations(ctx, r.Client, ingress, annotations)
+}
+
+// applyCertificateArnAnnotations applies the certificate ARN annotations of an
+// Ingress, the ones missing from annotations are removed.
+func applyCertificateArnAnnotations(ctx context.Context, c client.Client, ingress *networkingv1.Ingress, annotations map[string]string) error {
+	ingac := networkingv1ac.Ingress(ingress.GetName(), ingress.GetNamespace())
+	if len(annotations) > 0 {
+		ingac.WithAnnotations(annotations)
+	}
+	if err := c.Apply(ctx, ingac, client.FieldOwner(ACMManagerFieldManager), client.ForceOwnership); err != nil {
+		return err
+	}
 
-		_, err := r.clientset.NetworkingV1().Ingresses(ingress.Namespace).
-			Apply(ctx, ingac, metav1.ApplyOptions{FieldManager: ACMManagerFieldManager, Force: true})
+	updated := maps.Clone(ingress.GetAnnotations())
+	if updated == nil {
+		updated = map[string]string{}
+	}
+	for _, key := range []string{IngressCertificateArnKey, IngressManagedCertificateArnsKey} {
+		if value, ok := annotations[key]; ok {
+			updated[key] = value
+		} else {
+			delete(updated, key)
+		}
+	}
+	ingress.SetAnnotations(updated)
+	return nil
+}
+
+// certificateArnAnnotations returns the certificate ARN annotations of an
+// Ingress with the managed ARNs: the ARNs of the annotation that acm-manager
+// did not add are kept first, in their order, since the first one is the
+// default certificate of the ALB, followed by the managed ones. The ARNs
+// previously added by acm-manager are tracked in the managed ARNs annotation
+// and the ones no longer managed are removed. Empty annotations are left out
+// and changed is false when the annotations are up to date.
+func certificateArnAnnotations(ingress *networkingv1.Ingress, managed []string) (annotations map[string]string, changed bool) {
+	previous := splitArns(ingress.GetAnnotations()[IngressManagedCertificateArnsKey])
+	if _, ok := ingress.GetAnnotations()[IngressManagedCertificateArnsKey]; !ok && ownsCertificateArnAnnotation(ingress) {
+		// set before the managed ARNs were tracked, all the ARNs were added by acm-manager
+		previous = splitArns(ingress.GetAnnotations()[IngressCertificateArnKey])
+	}
+
+	var arns []string
+	for _, arn := range splitArns(ingress.GetAnnotations()[IngressCertificateArnKey]) {
+		if !slices.Contains(previous, arn) && !slices.Contains(managed, arn) && !slices.Contains(arns, arn) {
+			arns = append(arns, arn)
+		}
+	}
+	arns = append(arns, managed...)
+
+	annotations = map[string]string{}
+	if len(arns) > 0 {
+		annotations[IngressCertificateArnKey] = strings.Join(arns, ",")
+	}
+	if len(managed) > 0 {
+		annotations[IngressManagedCertificateArnsKey] = strings.Join(managed, ",")
+	}
+	for _, key := range []string{IngressCertificateArnKey, IngressManagedCertificateArnsKey} {
+		current, ok := ingress.GetAnnotations()[key]
+		value, set := annotations[key]
+		if ok != set || current != value {
+			changed = true
+		}
+	}
+	return annotations, changed
+}
+
+// ownsCertificateArnAnnotation returns true when the certificate ARN annotation
+// of an Ingress was last applied by acm-manager.
+func ownsCertificateArnAnnotation(ingress *networkingv1.Ingress) bool {
+	field := fmt.Sprintf("%q", "f:"+IngressCertificateArnKey)
+	return slices.ContainsFunc(ingress.GetManagedFields(), func(entry metav1.ManagedFieldsEntry) bool {
+		return entry.Manager == ACMManagerFieldManager && entry.FieldsV1 != nil &&
+			strings.Contains(string(entry.FieldsV1.Raw), field)
+	})
+}
+
+func splitArns(annotation string) []string {
+	var arns []string
+	for _, arn := range strings.Split(annotation, ",") {
+		if arn = strings.TrimSpace(arn); arn != "" {
+			arns = append(arns, arn)
+		}
+	}
+	return arns
+}
+
+// reconcileCertificateRef sets the ARN of the Certificate referenced by an
+// Ingress once issued. A Certificate of another namespace must be shared with
+// the namespace of the Ingress by a CertificateShare.
+func (r *IngressReconciler) reconcileCertificateRef(ctx context.Context, ingress *networkingv1.Ingress, ref string) (ctrl.Result, error) {
+	log := log.FromContext(ctx).WithValues("certificateRef", ref)
+
+	key, err := parseCertificateRef(ingress, ref)
+	if err == nil && key.Namespace != ingress.Namespace {
+		var shared bool
+		shared, err = r.isCertificateShared(ctx, key, ingress.Namespace)
 		if err != nil {
-			log.Error(err, "unable to update ingress with certificate arn")
+			log.Error(err, "unable to list certificate shares")
 			return ctrl.Result{}, err
 		}
+		if !shared {
+			err = fmt.Errorf("certificate %s is not shared with namespace %s by a CertificateShare", key, ingress.Namespace)
+		}
+	}
+	if err != nil {
+		// fixed by a change of the ingress or a certificate share
+		log.Info("invalid certificate reference", "reason", err.Error())
+		r.recorder.Event(ingress, core.EventTypeWarning, IngressEventInvalidCertificateRef, err.Error())
+		return ctrl.Result{}, nil
 	}
 
+	cert := &certificatev1beta1.Certificate{}
+	if err := r.Get(ctx, key, cert); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("waiting for the referenced certificate to be created")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch referenced certificate")
+		return ctrl.Result{}, err
+	}
+	if cert.Status.CertificateArn == "" || cert.Status.Status != certificatev1beta1.CertificateStatusIssued {
+		log.V(1).Info("waiting for the referenced certificate to be issued")
+		return ctrl.Result{
+			RequeueAfter: time.Second * 10,
+		}, nil
+	}
+
+	if err := r.setCertificateArns(ctx, ingress, []string{cert.Status.CertificateArn}); err != nil {
+		log.Error(err, "unable to update ingress with certificate arn")
+		return ctrl.Result{}, err
+	}
+	if err := r.deleteReplacedCertificates(ctx, ingress); err != nil {
+		log.Error(err, "unable to delete certificates replaced by the referenced one")
+		return ctrl.Result{}, err
+	}
 	return ctrl.Result{}, nil
 }
 
+// isCertificateShared returns true when a CertificateShare of the namespace of
+// a Certificate allows the Ingresses of namespace to reference it.
+func (r *IngressReconciler) isCertificateShared(ctx context.Context, cert types.NamespacedName, namespace string) (bool, error) {
+	shares := &certificatev1beta1.CertificateShareList{}
+	if err := r.List(ctx, shares, client.InNamespace(cert.Namespace)); err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(shares.Items, func(share certificatev1beta1.CertificateShare) bool {
+		return sharesCertificate(&share, cert.Name, namespace)
+	}), nil
+}
+
+// sharesCertificate returns true when a CertificateShare allows the Ingresses
+// of namespace to use the Certificate name of its namespace.
+func sharesCertificate(share *certificatev1beta1.CertificateShare, name, namespace string) bool {
+	from := slices.ContainsFunc(share.Spec.From, func(f certificatev1beta1.CertificateShareFrom) bool {
+		return f.Namespace == namespace
+	})
+	to := slices.ContainsFunc(share.Spec.To, func(t certificatev1beta1.CertificateShareTo) bool {
+		return t.Name == "" || t.Name == name
+	})
+	return from && to
+}
+
+// sharedCertificates returns the Certificates of other namespaces shared with
+// the Ingresses of namespace by CertificateShares.
+func (r *IngressReconciler) sharedCertificates(ctx context.Context, namespace string) ([]certificatev1beta1.Certificate, error) {
+	shares := &certificatev1beta1.CertificateShareList{}
+	if err := r.List(ctx, shares); err != nil {
+		return nil, err
+	}
+
+	var shared []certificatev1beta1.Certificate
+	listed := map[string][]certificatev1beta1.Certificate{}
+	for i := range shares.Items {
+		share := &shares.Items[i]
+		if share.Namespace == namespace {
+			continue
+		}
+		certs, ok := listed[share.Namespace]
+		if !ok {
+			list := &certificatev1beta1.CertificateList{}
+			if err := r.List(ctx, list, client.InNamespace(share.Namespace)); err != nil {
+				return nil, err
+			}
+			certs = list.Items
+			listed[share.Namespace] = certs
+		}
+		for _, cert := range certs {
+			if sharesCertificate(share, cert.Name, namespace) && !slices.ContainsFunc(shared, func(c certificatev1beta1.Certificate) bool {
+				return c.Namespace == cert.Namespace && c.Name == cert.Name
+			}) {
+				shared = append(shared, cert)
+			}
+		}
+	}
+	return shared, nil
+}
+
+// parseCertificateRef returns the Certificate of a <namespace>/<name> reference,
+// a <name> reference being in the namespace of the Ingress.
+func parseCertificateRef(ingress *networkingv1.Ingress, ref string) (types.NamespacedName, error) {
+	namespace, name, found := strings.Cut(ref, "/")
+	if !found {
+		namespace, name = ingress.Namespace, ref
+	}
+	if namespace == "" || name == "" || strings.Contains(name, "/") {
+		return types.NamespacedName{}, fmt.Errorf("certificate reference %q is not <namespace>/<name>", ref)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// optOut removes the ARNs added by acm-manager from an Ingress no longer
+// managed and deletes its Certificates, their ACM certificates being deleted
+// or retained according to their deletion policy.
+func (r *IngressReconciler) optOut(ctx context.Context, ingress *networkingv1.Ingress) error {
+	owned, err := ownedCertificates(ctx, r.Client, ingress)
+	if err != nil {
+		return err
+	}
+	annotations, changed := certificateArnAnnotations(ingress, nil)
+	if len(owned) == 0 && !changed {
+		return nil
+	}
+
+	if changed {
+		if err := applyCertificateArnAnnotations(ctx, r.Client, ingress, annotations); err != nil {
+			return err
+		}
+	}
+	for i := range owned {
+		if err := r.Delete(ctx, &owned[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("certificate of ingress no longer managed deleted", "certificate", owned[i].Name)
+	}
+	r.recorder.Event(ingress, core.EventTypeNormal, IngressEventOptedOut,
+		fmt.Sprintf("Certificate management disabled, %d certificate(s) deleted and managed ARNs removed", len(owned)))
+	return nil
+}
+
+// deleteReplacedCertificates deletes the Certificates generated for an Ingress
+// once their ARNs are replaced on the Ingress, by the ones of its IngressGroup
+// or of a reused Certificate.
+func (r *IngressReconciler) deleteReplacedCertificates(ctx context.Context, ingress *networkingv1.Ingress) error {
+	owned, err := ownedCertificates(ctx, r.Client, ingress)
+	if err != nil {
+		return err
+	}
+	arns := splitArns(ingress.GetAnnotations()[IngressCertificateArnKey])
+	for i := range owned {
+		if owned[i].Status.CertificateArn != "" && slices.Contains(arns, owned[i].Status.CertificateArn) {
+			continue
+		}
+		if err := r.Delete(ctx, &owned[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("certificate replaced by the ingress group one deleted", "certificate", owned[i].Name)
+	}
+	return nil
+}
+
+// reusableCertificate returns an issued Certificate covering all the hosts of
+// an Ingress, among the Certificates of its namespace and the ones shared with
+// it by CertificateShares. Only
+// the Certificates with a wildcard name that are not generated for an Ingress
+// are reused, since the generated ones are deleted with their Ingress, and only
+// the ones requested where the Certificates of the Ingress would be, so that
+// its ALB can use them.
+func (r *IngressReconciler) reusableCertificate(ctx context.Context, ingress *networkingv1.Ingress, hosts []string, region string) (*certificatev1beta1.Certificate, error) {
+	local := &certificatev1beta1.CertificateList{}
+	if err := r.List(ctx, local, client.InNamespace(ingress.Namespace)); err != nil {
+		return nil, err
+	}
+	shared, err := r.sharedCertificates(ctx, ingress.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, certs := range [][]certificatev1beta1.Certificate{local.Items, shared} {
+		slices.SortFunc(certs, func(a, b certificatev1beta1.Certificate) int {
+			return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+		})
+		for i := range certs {
+			cert := &certs[i]
+			if owner := metav1.GetControllerOf(cert); owner != nil && owner.Kind == "Ingress" {
+				continue
+			}
+			if !cert.DeletionTimestamp.IsZero() || cert.Status.CertificateArn == "" ||
+				cert.Status.Status != certificatev1beta1.CertificateStatusIssued {
+				continue
+			}
+			if !r.sameLocation(cert, region) {
+				continue
+			}
+			names := append([]string{cert.Spec.CommonName}, cert.Spec.SubjectAlternativeNames...)
+			if !slices.ContainsFunc(names, func(name string) bool { return strings.HasPrefix(name, "*.") }) {
+				continue
+			}
+			if !slices.ContainsFunc(hosts, func(host string) bool { return !certificateCoversHost(names, host) }) {
+				return cert, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// sameLocation returns true when the ACM certificate of a Certificate is
+// requested in the region with the controller credentials, like the ones
+// generated for Ingresses. An empty region is the one of the controller.
+func (r *IngressReconciler) sameLocation(cert *certificatev1beta1.Certificate, region string) bool {
+	if cert.Spec.IssuerRef != nil || cert.Status.RequestedIssuerRef != nil {
+		return false
+	}
+	effective := func(region string) string {
+		if region == "" {
+			return r.Region
+		}
+		return region
+	}
+	if effective(cert.Spec.Region) != effective(region) {
+		return false
+	}
+	// the current ACM certificate stays in the region it was requested in
+	// until it is replaced
+	return cert.Status.RequestedRegion == "" || effective(cert.Status.RequestedRegion) == effective(region)
+}
+
+// certificateCoversHost returns true when one of the names of a certificate
+// is valid for the host. A wildcard name only covers a single label.
+func certificateCoversHost(names []string, host string) bool {
+	host = strings.ToLower(host)
+	_, parent, _ := strings.Cut(host, ".")
+	return slices.ContainsFunc(names, func(name string) bool {
+		name = strings.ToLower(name)
+		return name == host || parent != "" && name == "*."+parent
+	})
+}
+
+// ownedCertificates returns the Certificates generated for an Ingress, in the
+// order of their number.
+func ownedCertificates(ctx context.Context, reader client.Reader, ingress *networkingv1.Ingress) ([]certificatev1beta1.Certificate, error) {
+	certs := &certificatev1beta1.CertificateList{}
+	if err := reader.List(ctx, certs, client.InNamespace(ingress.Namespace)); err != nil {
+		return nil, err
+	}
+
+	var owned []certificatev1beta1.Certificate
+	for _, cert := range certs.Items {
+		if isOwnedByIngress(&cert, ingress) {
+			owned = append(owned, cert)
+		}
+	}
+	sortCertificatesByName(owned)
+	return owned, nil
+}
+
+// isOwnedByIngress returns true when one of the owner references of a
+// Certificate is the Ingress, the Certificate may have other owners.
+func isOwnedByIngress(cert *certificatev1beta1.Certificate, ingress *networkingv1.Ingress) bool {
+	return slices.ContainsFunc(cert.GetOwnerReferences(), func(ref metav1.OwnerReference) bool {
+		return ref.Kind == "Ingress" && ref.UID == ingress.UID
+	})
+}
+
+// nameCollision reports a Certificate of the name of a generated one that is
+// not owned by the Ingress, as an event and an Ingress condition. The
+// Certificate is not modified.
+func (r *IngressReconciler) nameCollision(ctx context.Context, ingress *networkingv1.Ingress, cert *certificatev1beta1.Certificate) (ctrl.Result, error) {
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	existing := &certificatev1beta1.Certificate{}
+	if err := reader.Get(ctx, client.ObjectKeyFromObject(cert), existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			// deleted since, created again by the next reconcile
+			return ctrl.Result{RequeueAfter: time.Second}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if isOwnedByIngress(existing, ingress) {
+		// a Certificate of the Ingress the cache did not list yet
+		log.FromContext(ctx).V(1).Info("certificate of ingress not in cache yet", "certificate", cert.Name)
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
+	message := fmt.Sprintf("Certificate %s/%s already exists and is not owned by the ingress, set another name with the %s flag",
+		cert.Namespace, cert.Name, "--ingress-certificate-name-template")
+	log.FromContext(ctx).Info("certificate name collision", "certificate", cert.Name)
+	r.recorder.Event(ingress, core.EventTypeWarning, IngressEventCertificateNameCollision, message)
+
+	err := setIngressCondition(ctx, r.Client, ingress, metav1.Condition{
+		Type:    IngressConditionCertificateNameAvailable,
+		Status:  metav1.ConditionFalse,
+		Reason:  IngressReasonNameCollision,
+		Message: message,
+	})
+	return ctrl.Result{RequeueAfter: ingressNameCollisionRetryInterval}, err
+}
+
+// clearNameCollision sets the name collision condition of an Ingress back once
+// its Certificates are created.
+func (r *IngressReconciler) clearNameCollision(ctx context.Context, ingress *networkingv1.Ingress) error {
+	if ingressCondition(ingress, IngressConditionCertificateNameAvailable) == nil {
+		return nil
+	}
+	return setIngressCondition(ctx, r.Client, ingress, metav1.Condition{
+		Type:   IngressConditionCertificateNameAvailable,
+		Status: metav1.ConditionTrue,
+		Reason: IngressReasonNameAvailable,
+	})
+}
+
+// ingressCondition returns a condition of the conditions annotation of an Ingress.
+func ingressCondition(ingress *networkingv1.Ingress, conditionType string) *metav1.Condition {
+	var conditions []metav1.Condition
+	if err := json.Unmarshal([]byte(ingress.GetAnnotations()[IngressConditionsKey]), &conditions); err != nil {
+		return nil
+	}
+	return meta.FindStatusCondition(conditions, conditionType)
+}
+
+// setIngressCondition sets a condition in the conditions annotation of an
+// Ingress, the Ingress API having no status conditions. The annotation is
+// merge patched so the certificate ARN annotations applied by acm-manager are
+// left untouched.
+func setIngressCondition(ctx context.Context, c client.Client, ingress *networkingv1.Ingress, condition metav1.Condition) error {
+	var conditions []metav1.Condition
+	// an invalid annotation is replaced
+	_ = json.Unmarshal([]byte(ingress.GetAnnotations()[IngressConditionsKey]), &conditions)
+	condition.ObservedGeneration = ingress.Generation
+	if !meta.SetStatusCondition(&conditions, condition) {
+		return nil
+	}
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(ingress.DeepCopy())
+	if ingress.Annotations == nil {
+		ingress.Annotations = map[string]string{}
+	}
+	ingress.Annotations[IngressConditionsKey] = string(data)
+	if err := c.Patch(ctx, ingress, patch); err != nil {
+		return fmt.Errorf("unable to update conditions of ingress: %w", err)
+	}
+	return nil
+}
+
+// IngressCertificateName returns the name of the first Certificate generated
+// for an Ingress, from the IngressCertificateNameTemplate.
+func IngressCertificateName(ingress *networkingv1.Ingress) (string, error) {
+	var name strings.Builder
+	if err := IngressCertificateNameTemplate.Execute(&name, ingress.ObjectMeta); err != nil {
+		return "", fmt.Errorf("unable to execute certificate name template: %w", err)
+	}
+	if errs := validation.IsDNS1123Subdomain(name.String()); len(errs) > 0 {
+		return "", fmt.Errorf("invalid certificate name %q: %s", name.String(), strings.Join(errs, ", "))
+	}
+	return name.String(), nil
+}
+
+// ParseIngressCertificateNameTemplate parses a template of the names of the
+// Certificates generated for Ingresses, executed with the Ingress metadata.
+func ParseIngressCertificateNameTemplate(text string) (*template.Template, error) {
+	return template.New("certificate-name").Option("missingkey=error").Parse(text)
+}
+
+// sortCertificatesByName sorts the Certificates of a host partition in the
+// order of their number: <name>, <name>-2, ..., <name>-10.
+func sortCertificatesByName(certs []certificatev1beta1.Certificate) {
+	slices.SortFunc(certs, func(a, b certificatev1beta1.Certificate) int {
+		if len(a.Name) != len(b.Name) {
+			return len(a.Name) - len(b.Name)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// IngressCertificatePartition is a Certificate generated for some of the hosts
+// of an Ingress.
+type IngressCertificatePartition struct {
+	Name  string
+	Hosts []string
+}
+
+// IngressCertificatePartitions returns the Certificates generated for the
+// hosts of an Ingress: the hosts stay in the Certificates the Ingress owns and
+// the new Certificates are named after the base name.
+func IngressCertificatePartitions(ctx context.Context, reader client.Reader, ingress *networkingv1.Ingress, baseName string, hosts []string) ([]IngressCertificatePartition, error) {
+	owned, err := ownedCertificates(ctx, reader, ingress)
+	if err != nil {
+		return nil, err
+	}
+	previous := make([][]string, len(owned))
+	for i := range owned {
+		previous[i] = owned[i].Spec.SubjectAlternativeNames
+	}
+
+	var partitions []IngressCertificatePartition
+	for i, group := range partitionHosts(hosts, previous, IngressMaxCertificateHosts) {
+		if i >= len(owned) {
+			owned = append(owned, certificatev1beta1.Certificate{})
+			owned[i].Name = partitionCertificateName(baseName, owned[:i])
+		}
+		if len(group) > 0 {
+			partitions = append(partitions, IngressCertificatePartition{Name: owned[i].Name, Hosts: group})
+		}
+	}
+	return partitions, nil
+}
+
+// partitionCertificateName returns the name of a new Certificate of a host
+// partition: the base name for the first one, then suffixed by its number.
+func partitionCertificateName(base string, owned []certificatev1beta1.Certificate) string {
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+		if !slices.ContainsFunc(owned, func(c certificatev1beta1.Certificate) bool { return c.Name == name }) {
+			return name
+		}
+	}
+}
+
+// partitionHosts splits the hosts into groups of at most max hosts, one per
+// Certificate. Hosts stay in their previous group so that a new host only
+// changes a single group: new hosts fill the first groups with room, then new
+// groups. The groups are aligned with the previous ones and a group whose
+// hosts were all removed is returned empty.
+func partitionHosts(hosts []string, previous [][]string, max int) [][]string {
+	remaining := map[string]bool{}
+	for _, h := range hosts {
+		remaining[h] = true
+	}
+
+	groups := make([][]string, len(previous))
+	for i, group := range previous {
+		for _, h := range group {
+			if remaining[h] && len(groups[i]) < max {
+				groups[i] = append(groups[i], h)
+				delete(remaining, h)
+			}
+		}
+	}
+	for _, h := range hosts {
+		if !remaining[h] {
+			continue
+		}
+		i := slices.IndexFunc(groups, func(group []string) bool { return len(group) < max })
+		if i < 0 {
+			groups = append(groups, nil)
+			i = len(groups) - 1
+		}
+		groups[i] = append(groups[i], h)
+	}
+
+	for _, group := range groups {
+		slices.Sort(group)
+	}
+	return groups
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *IngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	log := log.FromContext(context.TODO())
@@ -176,31 +931,243 @@ func (r *IngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		log.Error(err, "unable to initialize certificate client")
 		return err
 	}
-	r.clientset, err = kubernetes.NewForConfig(mgr.GetConfig())
-	if err != nil {
-		log.Error(err, "unable to initialize kubernetes client")
-		return err
-	}
+	r.recorder = mgr.GetEventRecorderFor("Ingress")
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&networkingv1.Ingress{}).
-		Owns(&certificatev1alpha1.Certificate{}).
+		Owns(&certificatev1beta1.Certificate{}).
+		Watches(&certificatev1beta1.Certificate{}, handler.EnqueueRequestsFromMapFunc(r.ingressesForCertificate)).
+		Watches(&certificatev1beta1.CertificateShare{}, handler.EnqueueRequestsFromMapFunc(r.ingressesForShare)).
+		Watches(&networkingv1.IngressClass{}, handler.EnqueueRequestsFromMapFunc(r.ingressesForClass)).
 		Complete(r)
 }
 
-func isIngressShouldCreateCert(ingress *networkingv1.Ingress) bool {
+// ingressesForCertificate maps a Certificate that is not generated for an
+// Ingress to the Ingresses referencing it, using its ARN, or of wildcard
+// domains that may reuse it, so that they follow its changes and deletion.
+func (r *IngressReconciler) ingressesForCertificate(ctx context.Context, obj client.Object) []reconcile.Request {
+	if owner := metav1.GetControllerOf(obj); owner != nil && owner.Kind == "Ingress" {
+		return nil
+	}
+	cert := obj.(*certificatev1beta1.Certificate)
+
+	ingresses := &networkingv1.IngressList{}
+	if err := r.List(ctx, ingresses); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list ingresses")
+		return nil
+	}
+	shares := &certificatev1beta1.CertificateShareList{}
+	if err := r.List(ctx, shares, client.InNamespace(cert.Namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list certificate shares")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, ingress := range ingresses.Items {
+		arns := splitArns(ingress.GetAnnotations()[IngressCertificateArnKey])
+		ref, err := parseCertificateRef(&ingress, ingress.GetAnnotations()[IngressCertificateRefKey])
+		reuses := len(ingressWildcardDomains(&ingress)) > 0 && (ingress.Namespace == cert.Namespace ||
+			slices.ContainsFunc(shares.Items, func(share certificatev1beta1.CertificateShare) bool {
+				return sharesCertificate(&share, cert.Name, ingress.Namespace)
+			}))
+		if reuses || err == nil && ref == client.ObjectKeyFromObject(cert) ||
+			cert.Status.CertificateArn != "" && slices.Contains(arns, cert.Status.CertificateArn) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ingress.Name, Namespace: ingress.Namespace}})
+		}
+	}
+	return requests
+}
+
+// ingressesForShare maps a CertificateShare to the Ingresses of other
+// namespaces referencing a Certificate of its namespace, or of wildcard
+// domains that may reuse one.
+func (r *IngressReconciler) ingressesForShare(ctx context.Context, obj client.Object) []reconcile.Request {
+	share := obj.(*certificatev1beta1.CertificateShare)
+	ingresses := &networkingv1.IngressList{}
+	if err := r.List(ctx, ingresses); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list ingresses")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, ingress := range ingresses.Items {
+		if ingress.Namespace == share.Namespace {
+			continue
+		}
+		ref, err := parseCertificateRef(&ingress, ingress.GetAnnotations()[IngressCertificateRefKey])
+		// the old and new versions of an updated share are both mapped
+		reuses := len(ingressWildcardDomains(&ingress)) > 0 &&
+			slices.ContainsFunc(share.Spec.From, func(f certificatev1beta1.CertificateShareFrom) bool {
+				return f.Namespace == ingress.Namespace
+			})
+		if err == nil && ref.Namespace == share.Namespace || reuses {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ingress.Name, Namespace: ingress.Namespace}})
+		}
+	}
+	return requests
+}
+
+// ingressesForClass maps an IngressClass to the Ingresses naming it, and to
+// the ones without class since it may be the default one.
+func (r *IngressReconciler) ingressesForClass(ctx context.Context, obj client.Object) []reconcile.Request {
+	ingresses := &networkingv1.IngressList{}
+	if err := r.List(ctx, ingresses); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list ingresses")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, ingress := range ingresses.Items {
+		if name := ingressClassName(&ingress); name == "" || name == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ingress.Name, Namespace: ingress.Namespace}})
+		}
+	}
+	return requests
+}
+
+// IsIngressShouldCreateCert returns true when a Certificate is generated for
+// the Ingress, or a referenced one is used. Without the enable annotation,
+// Ingresses referencing a Certificate and internet-facing Ingresses of an
+// IngressClass of one of the IngressClassControllers are auto detected.
+func IsIngressShouldCreateCert(ctx context.Context, reader client.Reader, ingress *networkingv1.Ingress) (bool, error) {
 	enabled := strings.ToLower(ingress.GetAnnotations()[ACMManagerCreateCertificateKey])
 	if enabled == "true" || enabled == "yes" {
-		return true
+		return true, nil
 	}
 	if enabled == "false" || enabled == "no" {
-		return false
+		return false, nil
+	}
+	if ingress.GetAnnotations()[IngressCertificateRefKey] != "" {
+		return true, nil
+	}
+
+	if !IngressAutoDetect || ingress.GetAnnotations()[IngressSchemeKey] != IngressSchemeValue {
+		return false, nil
+	}
+
+	return isIngressClassControlled(ctx, reader, ingress)
+}
+
+// isIngressClassControlled returns true when the class of an Ingress, or the
+// default IngressClass when it has none, has one of the IngressClassControllers.
+func isIngressClassControlled(ctx context.Context, reader client.Reader, ingress *networkingv1.Ingress) (bool, error) {
+	name := ingressClassName(ingress)
+	if name == "" {
+		classes := &networkingv1.IngressClassList{}
+		if err := reader.List(ctx, classes); err != nil {
+			return false, fmt.Errorf("unable to list ingress classes: %w", err)
+		}
+		for _, class := range classes.Items {
+			if class.Annotations[networkingv1.AnnotationIsDefaultIngressClass] == "true" &&
+				slices.Contains(IngressClassControllers, class.Spec.Controller) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	class := &networkingv1.IngressClass{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: name}, class); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the legacy class of the AWS Load Balancer Controller has no IngressClass
+			return name == IngressClassValue, nil
+		}
+		return false, fmt.Errorf("unable to fetch ingress class %s: %w", name, err)
+	}
+	return slices.Contains(IngressClassControllers, class.Spec.Controller), nil
+}
+
+// ingressClassName returns the class of an Ingress, from the legacy annotation
+// when spec.ingressClassName is not set.
+func ingressClassName(ingress *networkingv1.Ingress) string {
+	if ingress.Spec.IngressClassName != nil {
+		return *ingress.Spec.IngressClassName
+	}
+	return ingress.GetAnnotations()[IngressClassAnnotationKey]
+}
+
+// IngressHosts returns the sorted hosts of the generated Certificate: the hosts
+// of the TLS entries, of the rules or of both depending on the host source,
+// filtered by the include and exclude patterns, and the extra SANs. Hosts of
+// TLS entries that specify a secret are left out.
+func IngressHosts(ingress *networkingv1.Ingress) []string {
+	source := ingress.GetAnnotations()[IngressHostSourceKey]
+	if source != HostSourceTLS && source != HostSourceRules && source != HostSourceAll {
+		source = IngressHostSource
 	}
 
-	if IngressAutoDetect && ingress.Spec.IngressClassName != nil && *ingress.Spec.IngressClassName == IngressClassValue &&
-		ingress.GetAnnotations()[IngressSchemeKey] == IngressSchemeValue {
-		return true
+	hostMap := map[string]bool{}
+	withSecret := map[string]bool{}
+	for _, t := range ingress.Spec.TLS {
+		for _, h := range t.Hosts {
+			if t.SecretName != "" {
+				withSecret[h] = true
+			} else if source != HostSourceRules {
+				hostMap[h] = true
+			}
+		}
 	}
+	if source != HostSourceTLS {
+		for _, rule := range ingress.Spec.Rules {
+			if rule.Host != "" {
+				hostMap[rule.Host] = true
+			}
+		}
+	}
+
+	include := hostPatterns(ingress.GetAnnotations()[IngressIncludeHostsKey])
+	exclude := hostPatterns(ingress.GetAnnotations()[IngressExcludeHostsKey])
+	wildcardDomains := ingressWildcardDomains(ingress)
+	hosts := make([]string, 0, len(hostMap))
+	for h := range hostMap {
+		if withSecret[h] || len(include) > 0 && !matchesAnyHost(include, h) || matchesAnyHost(exclude, h) {
+			continue
+		}
+		hosts = append(hosts, wildcardHost(wildcardDomains, h))
+	}
+	// the invalid names are reported by the controller
+	extraSANs, _ := ingressExtraSANs(ingress)
+	hosts = append(hosts, extraSANs...)
+	slices.Sort(hosts)
+
+	return slices.Compact(hosts)
+}
+
+// ingressWildcardDomains returns the parent domains whose hosts are collapsed
+// into a wildcard name, from the annotation or the global ones.
+func ingressWildcardDomains(ingress *networkingv1.Ingress) []string {
+	if annotation, ok := ingress.GetAnnotations()[IngressWildcardDomainsKey]; ok {
+		return hostPatterns(annotation)
+	}
+	return IngressWildcardDomains
+}
+
+// wildcardHost returns *.<domain> when the host is directly below one of the
+// domains, the host otherwise.
+func wildcardHost(domains []string, host string) string {
+	_, parent, _ := strings.Cut(host, ".")
+	if parent != "" && slices.ContainsFunc(domains, func(domain string) bool {
+		return strings.EqualFold(strings.TrimSuffix(domain, "."), parent)
+	}) {
+		return "*." + parent
+	}
+	return host
+}
+
+func hostPatterns(annotation string) []string {
+	var patterns []string
+	for _, p := range strings.Split(annotation, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
 
-	return false
+// matchesAnyHost returns true when the host matches one of the patterns, a
+// *.<domain> pattern matching any name below the domain.
+func matchesAnyHost(patterns []string, host string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		return policy.MatchDomain(pattern, host)
+	})
 }
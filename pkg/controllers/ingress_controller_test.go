@@ -18,15 +18,25 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"reflect"
+	"text/template"
 	"time"
 
-	certificatev1alpha1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1alpha1"
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+	certificatefake "vdesjardins/acm-manager/pkg/client/versioned/fake"
 
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("Ingress controller", func() {
@@ -46,7 +56,7 @@ var _ = Describe("Ingress controller", func() {
 			Expect(k8sClient.Create(ctx, ing)).Should(Succeed())
 
 			lookupKey := types.NamespacedName{Name: ingName, Namespace: ingNamespace}
-			createdCert := &certificatev1alpha1.Certificate{}
+			createdCert := &certificatev1beta1.Certificate{}
 
 			By("By checking that certificate is created")
 			Eventually(func() bool {
@@ -75,6 +85,617 @@ var _ = Describe("Ingress controller", func() {
 	})
 })
 
+var _ = Describe("Ingress auto detection", func() {
+	newIngressClass := func(name, controller string, isDefault bool) *networkingv1.IngressClass {
+		class := &networkingv1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       networkingv1.IngressClassSpec{Controller: controller},
+		}
+		if isDefault {
+			class.Annotations = map[string]string{networkingv1.AnnotationIsDefaultIngressClass: "true"}
+		}
+		return class
+	}
+
+	albPublic := newIngressClass("alb-public", "ingress.k8s.aws/alb", false)
+	nginx := newIngressClass("nginx", "k8s.io/ingress-nginx", false)
+	defaultALB := newIngressClass("alb-default", "ingress.k8s.aws/alb", true)
+
+	table.DescribeTable("Should detect internet-facing Ingresses of the ALB controller",
+		func(reader client.Reader, className *string, legacyClass string, expected bool) {
+			ingress := newIngress("test", "default")
+			ingress.Annotations = map[string]string{IngressSchemeKey: IngressSchemeValue}
+			ingress.Spec.IngressClassName = className
+			if legacyClass != "" {
+				ingress.Annotations[IngressClassAnnotationKey] = legacyClass
+			}
+
+			shouldCreate, err := IsIngressShouldCreateCert(context.Background(), reader, ingress)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(shouldCreate).To(Equal(expected))
+		},
+		table.Entry("class of the ALB controller", newFakeClient(albPublic, nginx), ptr.To("alb-public"), "", true),
+		table.Entry("class of another controller", newFakeClient(albPublic, nginx), ptr.To("nginx"), "", false),
+		table.Entry("legacy annotation", newFakeClient(albPublic, nginx), nil, "alb-public", true),
+		table.Entry("legacy alb class without IngressClass", newFakeClient(), nil, "alb", true),
+		table.Entry("default class of the ALB controller", newFakeClient(defaultALB, nginx), nil, "", true),
+		table.Entry("no default class", newFakeClient(albPublic, nginx), nil, "", false),
+	)
+
+	It("Should only detect the configured controllers", func() {
+		defer func(controllers []string) { IngressClassControllers = controllers }(IngressClassControllers)
+		IngressClassControllers = []string{"k8s.io/ingress-nginx"}
+
+		ingress := newIngress("test", "default")
+		ingress.Annotations = map[string]string{IngressSchemeKey: IngressSchemeValue}
+		ingress.Spec.IngressClassName = ptr.To("nginx")
+
+		shouldCreate, err := IsIngressShouldCreateCert(context.Background(), newFakeClient(nginx), ingress)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shouldCreate).To(BeTrue())
+	})
+})
+
+var _ = Describe("Ingress hosts", func() {
+	newHostsIngress := func(annotations map[string]string) *networkingv1.Ingress {
+		ingress := newIngress("test", "default")
+		ingress.Annotations = annotations
+		ingress.Spec.Rules = []networkingv1.IngressRule{
+			{Host: "www.example.com"},
+			{Host: "api.example.com"},
+			{Host: "admin.internal.example.com"},
+			{Host: "secret.example.com"},
+		}
+		ingress.Spec.TLS = []networkingv1.IngressTLS{
+			{Hosts: []string{"tls.example.com"}},
+			{Hosts: []string{"secret.example.com"}, SecretName: "secret-tls"},
+		}
+		return ingress
+	}
+
+	table.DescribeTable("Should take the hosts of the host source",
+		func(annotations map[string]string, expected []string) {
+			Expect(IngressHosts(newHostsIngress(annotations))).To(Equal(expected))
+		},
+		table.Entry("tls by default", nil, []string{"tls.example.com"}),
+		table.Entry("rules", map[string]string{IngressHostSourceKey: HostSourceRules},
+			[]string{"admin.internal.example.com", "api.example.com", "www.example.com"}),
+		table.Entry("all", map[string]string{IngressHostSourceKey: HostSourceAll},
+			[]string{"admin.internal.example.com", "api.example.com", "tls.example.com", "www.example.com"}),
+		table.Entry("excluded hosts", map[string]string{
+			IngressHostSourceKey:   HostSourceAll,
+			IngressExcludeHostsKey: "*.internal.example.com, tls.example.com",
+		}, []string{"api.example.com", "www.example.com"}),
+		table.Entry("included hosts", map[string]string{
+			IngressHostSourceKey:   HostSourceRules,
+			IngressIncludeHostsKey: "*.example.com",
+			IngressExcludeHostsKey: "api.example.com",
+		}, []string{"admin.internal.example.com", "www.example.com"}),
+	)
+
+	It("Should use the global host source without annotation", func() {
+		defer func(source string) { IngressHostSource = source }(IngressHostSource)
+		IngressHostSource = HostSourceRules
+
+		Expect(IngressHosts(newHostsIngress(nil))).To(Equal([]string{"admin.internal.example.com", "api.example.com", "www.example.com"}))
+		Expect(IngressHosts(newHostsIngress(map[string]string{IngressHostSourceKey: HostSourceTLS}))).To(Equal([]string{"tls.example.com"}))
+	})
+})
+
+var _ = Describe("Ingress wildcard domains", func() {
+	newPreviewIngress := func(annotations map[string]string, hosts ...string) *networkingv1.Ingress {
+		ingress := newIngress("preview", "default")
+		for k, v := range annotations {
+			ingress.Annotations[k] = v
+		}
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: hosts}}
+		return ingress
+	}
+
+	It("Should collapse the hosts directly below the wildcard domains", func() {
+		ingress := newPreviewIngress(map[string]string{IngressWildcardDomainsKey: "preview.example.com"},
+			"pr-1.preview.example.com", "api.pr-1.preview.example.com", "preview.example.com", "www.example.com")
+		Expect(IngressHosts(ingress)).To(Equal([]string{
+			"*.preview.example.com", "api.pr-1.preview.example.com", "preview.example.com", "www.example.com",
+		}))
+
+		defer func(domains []string) { IngressWildcardDomains = domains }(IngressWildcardDomains)
+		IngressWildcardDomains = []string{"example.com"}
+		Expect(IngressHosts(newPreviewIngress(nil, "a.example.com", "b.example.com"))).To(Equal([]string{"*.example.com"}))
+		Expect(IngressHosts(newPreviewIngress(map[string]string{IngressWildcardDomainsKey: ""}, "a.example.com"))).To(Equal([]string{"a.example.com"}))
+	})
+
+	table.DescribeTable("Should check the hosts covered by a certificate",
+		func(host string, expected bool) {
+			Expect(certificateCoversHost([]string{"*.preview.example.com", "www.example.com"}, host)).To(Equal(expected))
+		},
+		table.Entry("exact name", "www.example.com", true),
+		table.Entry("single label below the wildcard", "pr-1.preview.example.com", true),
+		table.Entry("wildcard name", "*.preview.example.com", true),
+		table.Entry("two labels below the wildcard", "api.pr-1.preview.example.com", false),
+		table.Entry("wildcard domain", "preview.example.com", false),
+	)
+
+	It("Should reuse the wildcard certificates of the namespace or shared with it", func() {
+		ctx := context.Background()
+		newWildcardCert := func(name, namespace string, labels map[string]string) *certificatev1beta1.Certificate {
+			return &certificatev1beta1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+				Spec:       certificatev1beta1.CertificateSpec{CommonName: "*.preview.example.com"},
+				Status: certificatev1beta1.CertificateStatus{
+					CertificateArn: "arn-" + name,
+					Status:         certificatev1beta1.CertificateStatusIssued,
+				},
+			}
+		}
+		generated := newWildcardCert("generated", "default", nil)
+		generated.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "networking.k8s.io/v1", Kind: "Ingress", Name: "other", UID: "uid", Controller: ptr.To(true),
+		}}
+		ingress := newPreviewIngress(map[string]string{IngressWildcardDomainsKey: "preview.example.com"}, "pr-1.preview.example.com")
+
+		newReconciler := func(objs ...client.Object) *IngressReconciler {
+			return &IngressReconciler{Client: newFakeClient(objs...)}
+		}
+
+		r := newReconciler(generated, newWildcardCert("other-namespace", "team-a", nil))
+		cert, err := r.reusableCertificate(ctx, ingress, IngressHosts(ingress), "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert).To(BeNil())
+
+		share := &certificatev1beta1.CertificateShare{
+			ObjectMeta: metav1.ObjectMeta{Name: "preview", Namespace: "team-a"},
+			Spec: certificatev1beta1.CertificateShareSpec{
+				From: []certificatev1beta1.CertificateShareFrom{{Namespace: "default"}},
+				To:   []certificatev1beta1.CertificateShareTo{{Name: "shared"}},
+			},
+		}
+		r = newReconciler(generated, newWildcardCert("other-namespace", "team-a", nil), newWildcardCert("shared", "team-a", nil), share)
+		cert, err = r.reusableCertificate(ctx, ingress, IngressHosts(ingress), "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Status.CertificateArn).To(Equal("arn-shared"))
+
+		r = newReconciler(newWildcardCert("local", "default", nil), newWildcardCert("shared", "team-a", nil), share)
+		cert, err = r.reusableCertificate(ctx, ingress, []string{"*.preview.example.com", "www.example.com"}, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert).To(BeNil())
+		cert, err = r.reusableCertificate(ctx, ingress, IngressHosts(ingress), "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Status.CertificateArn).To(Equal("arn-local"))
+	})
+
+	It("Should only reuse the wildcard certificates of the region of the ingress", func() {
+		ctx := context.Background()
+		newRegionCert := func(name, region string, issuerRef *certificatev1beta1.IssuerReference) *certificatev1beta1.Certificate {
+			return &certificatev1beta1.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: certificatev1beta1.CertificateSpec{
+					CommonName: "*.preview.example.com",
+					Region:     region,
+					IssuerRef:  issuerRef,
+				},
+				Status: certificatev1beta1.CertificateStatus{
+					CertificateArn:  "arn-" + name,
+					Status:          certificatev1beta1.CertificateStatusIssued,
+					RequestedRegion: region,
+				},
+			}
+		}
+		ingress := newPreviewIngress(map[string]string{IngressWildcardDomainsKey: "preview.example.com"}, "pr-1.preview.example.com")
+		hosts := IngressHosts(ingress)
+
+		r := &IngressReconciler{
+			Client: newFakeClient(
+				newRegionCert("a-issuer", "", &certificatev1beta1.IssuerReference{Name: "other-account"}),
+				newRegionCert("b-us-east-1", "us-east-1", nil),
+				newRegionCert("c-default", "", nil),
+			),
+			Region: "ca-central-1",
+		}
+
+		cert, err := r.reusableCertificate(ctx, ingress, hosts, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Name).To(Equal("c-default"))
+
+		cert, err = r.reusableCertificate(ctx, ingress, hosts, "ca-central-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Name).To(Equal("c-default"))
+
+		cert, err = r.reusableCertificate(ctx, ingress, hosts, "us-east-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert.Name).To(Equal("b-us-east-1"))
+
+		cert, err = r.reusableCertificate(ctx, ingress, hosts, "eu-west-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cert).To(BeNil())
+	})
+})
+
+var _ = Describe("Ingress certificate arns", func() {
+	table.DescribeTable("Should keep the certificate arns added by users",
+		func(arns, managedArns string, managed []string, expected map[string]string) {
+			ingress := newIngress("test", "default")
+			if arns != "" {
+				ingress.Annotations[IngressCertificateArnKey] = arns
+			}
+			if managedArns != "-" {
+				ingress.Annotations[IngressManagedCertificateArnsKey] = managedArns
+			}
+			annotations, changed := certificateArnAnnotations(ingress, managed)
+			Expect(annotations).To(Equal(expected))
+			Expect(changed).To(Equal(!reflect.DeepEqual(expected, map[string]string{
+				IngressCertificateArnKey: arns, IngressManagedCertificateArnsKey: managedArns,
+			})))
+		},
+		table.Entry("new ingress", "", "-", []string{"arn-1"}, map[string]string{
+			IngressCertificateArnKey: "arn-1", IngressManagedCertificateArnsKey: "arn-1",
+		}),
+		table.Entry("user arn", "arn-ev", "-", []string{"arn-1"}, map[string]string{
+			IngressCertificateArnKey: "arn-ev,arn-1", IngressManagedCertificateArnsKey: "arn-1",
+		}),
+		table.Entry("replaced managed arn", "arn-ev,arn-1", "arn-1", []string{"arn-2", "arn-3"}, map[string]string{
+			IngressCertificateArnKey: "arn-ev,arn-2,arn-3", IngressManagedCertificateArnsKey: "arn-2,arn-3",
+		}),
+		table.Entry("user arn added after the managed one", "arn-1, arn-ev", "arn-1", []string{"arn-1"}, map[string]string{
+			IngressCertificateArnKey: "arn-ev,arn-1", IngressManagedCertificateArnsKey: "arn-1",
+		}),
+		table.Entry("up to date", "arn-ev,arn-1", "arn-1", []string{"arn-1"}, map[string]string{
+			IngressCertificateArnKey: "arn-ev,arn-1", IngressManagedCertificateArnsKey: "arn-1",
+		}),
+		table.Entry("no longer managed", "arn-ev,arn-1", "arn-1", nil, map[string]string{
+			IngressCertificateArnKey: "arn-ev",
+		}),
+	)
+
+	It("Should replace the arns applied before they were tracked", func() {
+		ingress := newIngress("test", "default")
+		ingress.Annotations[IngressCertificateArnKey] = "arn-1"
+		annotations, _ := certificateArnAnnotations(ingress, []string{"arn-2"})
+		Expect(annotations).To(HaveKeyWithValue(IngressCertificateArnKey, "arn-1,arn-2"))
+
+		ingress.ManagedFields = []metav1.ManagedFieldsEntry{{
+			Manager:  ACMManagerFieldManager,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{"f:alb.ingress.kubernetes.io/certificate-arn":{}}}}`)},
+		}}
+		annotations, _ = certificateArnAnnotations(ingress, []string{"arn-2"})
+		Expect(annotations).To(HaveKeyWithValue(IngressCertificateArnKey, "arn-2"))
+	})
+
+	It("Should clean up the ingresses opting out", func() {
+		ctx := context.Background()
+		ingress := newIngress("test", "default")
+		ingress.UID = "ingress-uid"
+		ingress.Annotations[IngressCertificateArnKey] = "arn-ev"
+		cert := &certificatev1beta1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec:       certificatev1beta1.CertificateSpec{CommonName: "test.local"},
+		}
+		Expect(ctrl.SetControllerReference(ingress, cert, testScheme)).To(Succeed())
+
+		recorder := record.NewFakeRecorder(10)
+		r := &IngressReconciler{
+			Client:   newFakeClient(ingress, cert),
+			Scheme:   testScheme,
+			recorder: recorder,
+		}
+		Expect(r.setCertificateArns(ctx, ingress, []string{"arn-1"})).To(Succeed())
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: "test", Namespace: "default"}, ingress)).To(Succeed())
+		Expect(ingress.Annotations).To(HaveKeyWithValue(IngressCertificateArnKey, "arn-ev,arn-1"))
+		ingress.Annotations[ACMManagerCreateCertificateKey] = "false"
+		Expect(r.Update(ctx, ingress)).To(Succeed())
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "test", Namespace: "default"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: "test", Namespace: "default"}, ingress)).To(Succeed())
+		Expect(ingress.Annotations).To(HaveKeyWithValue(IngressCertificateArnKey, "arn-ev"))
+		Expect(ingress.Annotations).NotTo(HaveKey(IngressManagedCertificateArnsKey))
+		Expect(apierrors.IsNotFound(r.Get(ctx, types.NamespacedName{Name: "test", Namespace: "default"}, cert))).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring(IngressEventOptedOut)))
+
+		By("doing nothing once cleaned up")
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "test", Namespace: "default"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+})
+
+var _ = Describe("Ingress certificate references", func() {
+	newReferencingIngress := func(namespace, ref string) *networkingv1.Ingress {
+		ingress := newIngress("web", namespace)
+		ingress.Annotations = map[string]string{IngressCertificateRefKey: ref}
+		return ingress
+	}
+
+	It("Should use the arn of the referenced certificate once issued", func() {
+		ctx := context.Background()
+		cert := &certificatev1beta1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "platform"},
+			Spec:       certificatev1beta1.CertificateSpec{CommonName: "www.example.com"},
+		}
+		share := &certificatev1beta1.CertificateShare{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "platform"},
+			Spec: certificatev1beta1.CertificateShareSpec{
+				From: []certificatev1beta1.CertificateShareFrom{{Namespace: "team-a"}},
+				To:   []certificatev1beta1.CertificateShareTo{{Name: "shared"}},
+			},
+		}
+		recorder := record.NewFakeRecorder(10)
+		r := &IngressReconciler{
+			Client:   newFakeClient(cert, share, newReferencingIngress("team-a", "platform/shared"), newReferencingIngress("team-b", "platform/shared")),
+			Scheme:   testScheme,
+			recorder: recorder,
+		}
+		teamA := ctrl.Request{NamespacedName: types.NamespacedName{Name: "web", Namespace: "team-a"}}
+		teamB := ctrl.Request{NamespacedName: types.NamespacedName{Name: "web", Namespace: "team-b"}}
+
+		By("waiting for the certificate to be issued")
+		result, err := r.Reconcile(ctx, teamA)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).NotTo(BeZero())
+
+		cert.Status.CertificateArn = "shared-arn"
+		cert.Status.Status = certificatev1beta1.CertificateStatusIssued
+		Expect(r.Status().Update(ctx, cert)).To(Succeed())
+		_, err = r.Reconcile(ctx, teamA)
+		Expect(err).NotTo(HaveOccurred())
+
+		ingress := &networkingv1.Ingress{}
+		Expect(r.Get(ctx, teamA.NamespacedName, ingress)).To(Succeed())
+		Expect(ingress.Annotations).To(HaveKeyWithValue(IngressCertificateArnKey, "shared-arn"))
+		certs := &certificatev1beta1.CertificateList{}
+		Expect(r.List(ctx, certs, client.InNamespace("team-a"))).To(Succeed())
+		Expect(certs.Items).To(BeEmpty())
+
+		By("refusing the namespaces the certificate is not shared with")
+		_, err = r.Reconcile(ctx, teamB)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, teamB.NamespacedName, ingress)).To(Succeed())
+		Expect(ingress.Annotations).NotTo(HaveKey(IngressCertificateArnKey))
+		Expect(recorder.Events).To(Receive(ContainSubstring(IngressEventInvalidCertificateRef)))
+		Expect(r.ingressesForShare(ctx, share)).To(HaveLen(2))
+		Expect(r.ingressesForCertificate(ctx, cert)).To(HaveLen(2))
+	})
+
+	table.DescribeTable("Should parse the certificate references",
+		func(ref string, expected types.NamespacedName, valid bool) {
+			key, err := parseCertificateRef(newReferencingIngress("team-a", ref), ref)
+			Expect(err == nil).To(Equal(valid))
+			Expect(key).To(Equal(expected))
+		},
+		table.Entry("namespace and name", "platform/shared", types.NamespacedName{Namespace: "platform", Name: "shared"}, true),
+		table.Entry("name", "shared", types.NamespacedName{Namespace: "team-a", Name: "shared"}, true),
+		table.Entry("empty name", "platform/", types.NamespacedName{}, false),
+		table.Entry("too many parts", "a/b/c", types.NamespacedName{}, false),
+	)
+})
+
+var _ = Describe("Ingress certificate names", func() {
+	AfterEach(func() {
+		IngressCertificateNameTemplate = template.Must(ParseIngressCertificateNameTemplate("{{ .Name }}"))
+	})
+
+	It("Should name the certificates from the template", func() {
+		ingress := newIngress("web", "default")
+		Expect(IngressCertificateName(ingress)).To(Equal("web"))
+
+		IngressCertificateNameTemplate = template.Must(ParseIngressCertificateNameTemplate("{{ .Namespace }}-{{ .Name }}-acm"))
+		Expect(IngressCertificateName(ingress)).To(Equal("default-web-acm"))
+
+		IngressCertificateNameTemplate = template.Must(ParseIngressCertificateNameTemplate("{{ .Name }}_ACM"))
+		_, err := IngressCertificateName(ingress)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should report the certificates of the name not owned by the ingress", func() {
+		ctx := context.Background()
+		ingress := newIngress("web", "default")
+		ingress.UID = "ingress-uid"
+		existing := &certificatev1beta1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       certificatev1beta1.CertificateSpec{CommonName: "other.local"},
+		}
+		recorder := record.NewFakeRecorder(10)
+		r := &IngressReconciler{
+			Client:   newFakeClient(ingress, existing),
+			Scheme:   testScheme,
+			recorder: recorder,
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "web", Namespace: "default"}}
+
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring(IngressEventCertificateNameCollision)))
+		Expect(r.Get(ctx, request.NamespacedName, ingress)).To(Succeed())
+		condition := ingressCondition(ingress, IngressConditionCertificateNameAvailable)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(IngressReasonNameCollision))
+		Expect(r.Get(ctx, request.NamespacedName, existing)).To(Succeed())
+		Expect(existing.Spec.CommonName).To(Equal("other.local"))
+
+		By("creating the certificate once named after the template")
+		IngressCertificateNameTemplate = template.Must(ParseIngressCertificateNameTemplate("{{ .Name }}-acm"))
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		cert := &certificatev1beta1.Certificate{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "web-acm", Namespace: "default"}, cert)).To(Succeed())
+		Expect(isOwnedByIngress(cert, ingress)).To(BeTrue())
+		Expect(r.Get(ctx, request.NamespacedName, ingress)).To(Succeed())
+		Expect(ingressCondition(ingress, IngressConditionCertificateNameAvailable).Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("Should not report the certificates of the ingress missing from the cache", func() {
+		ctx := context.Background()
+		ingress := newIngress("web", "default")
+		ingress.UID = "ingress-uid"
+		owned := &certificatev1beta1.Certificate{ObjectMeta: metav1.ObjectMeta{
+			Name: "web", Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "networking.k8s.io/v1", Kind: "Ingress", Name: "web", UID: "ingress-uid", Controller: ptr.To(true)},
+			},
+		}}
+		recorder := record.NewFakeRecorder(10)
+		r := &IngressReconciler{
+			Client:    newFakeClient(ingress),
+			APIReader: newFakeClient(owned),
+			Scheme:    testScheme,
+			recorder:  recorder,
+		}
+
+		result, err := r.nameCollision(ctx, ingress, &certificatev1beta1.Certificate{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).NotTo(BeZero())
+		Expect(recorder.Events).NotTo(Receive())
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(ingress), ingress)).To(Succeed())
+		Expect(ingressCondition(ingress, IngressConditionCertificateNameAvailable)).To(BeNil())
+	})
+
+	It("Should manage the certificates with several owners", func() {
+		ingress := newIngress("web", "default")
+		ingress.UID = "ingress-uid"
+		cert := &certificatev1beta1.Certificate{ObjectMeta: metav1.ObjectMeta{
+			Name: "web", Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "web", UID: "configmap-uid"},
+				{APIVersion: "networking.k8s.io/v1", Kind: "Ingress", Name: "web", UID: "ingress-uid"},
+			},
+		}}
+		Expect(isOwnedByIngress(cert, ingress)).To(BeTrue())
+
+		cert.OwnerReferences = cert.OwnerReferences[:1]
+		Expect(isOwnedByIngress(cert, ingress)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Ingress certificate options", func() {
+	table.DescribeTable("Should reject the invalid annotations",
+		func(key, value string) {
+			ingress := newIngress("web", "default")
+			ingress.Annotations[key] = value
+			_, err := parseIngressCertificateOptions(ingress)
+			Expect(err).To(MatchError(ContainSubstring(key)))
+		},
+		table.Entry("key algorithm", IngressKeyAlgorithmKey, "RSA_1024"),
+		table.Entry("region", IngressRegionKey, "Canada"),
+		table.Entry("deletion policy", IngressDeletionPolicyKey, "Orphan"),
+		table.Entry("validation method", IngressValidationMethodKey, "HTTP"),
+		table.Entry("tag without key", IngressTagsKey, "team=web,=value"),
+		table.Entry("reserved tag", IngressTagsKey, "acm-manager/owner=me"),
+		table.Entry("extra san", IngressExtraSANsKey, "www.test.local,bad_name.local"),
+	)
+
+	It("Should add the valid extra sans to the hosts", func() {
+		ingress := newIngress("web", "default")
+		ingress.Annotations[IngressExtraSANsKey] = "www.test.local, *.test.local,*.local-only"
+		Expect(IngressHosts(ingress)).To(Equal([]string{"*.test.local", "test.local", "www.test.local"}))
+	})
+
+	It("Should clear the options of the removed annotations", func() {
+		spec := certificatev1beta1.CertificateSpec{
+			Region:     "ca-central-1",
+			Tags:       map[string]string{"team": "web"},
+			Options:    &certificatev1beta1.CertificateOptions{KeyAlgorithm: certificatev1beta1.CertificateKeyAlgorithmRSA2048},
+			Validation: &certificatev1beta1.CertificateValidation{Method: certificatev1beta1.CertificateValidationMethodDNS},
+		}
+		options, err := parseIngressCertificateOptions(newIngress("web", "default"))
+		Expect(err).NotTo(HaveOccurred())
+		options.applyTo(&spec)
+		Expect(spec).To(Equal(certificatev1beta1.CertificateSpec{}))
+	})
+
+	It("Should apply the certificates with only a key algorithm", func() {
+		ctx := context.Background()
+		ingress := newIngress("web", "default")
+		ingress.Annotations[IngressKeyAlgorithmKey] = string(certificatev1beta1.CertificateKeyAlgorithmECPrime256v1)
+		r := &IngressReconciler{
+			Client:   newFakeClient(ingress),
+			Scheme:   testScheme,
+			recorder: record.NewFakeRecorder(10),
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "web", Namespace: "default"}}
+
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		cert := &certificatev1beta1.Certificate{}
+		Expect(r.Get(ctx, request.NamespacedName, cert)).To(Succeed())
+		Expect(cert.Spec.Options.KeyAlgorithm).To(Equal(certificatev1beta1.CertificateKeyAlgorithmECPrime256v1))
+
+		By("leaving the unset enum fields out of the applied spec")
+		r.certClient = certificatefake.NewSimpleClientset(cert.DeepCopy())
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		applied, err := r.certClient.AcmmanagerV1beta1().Certificates("default").Get(ctx, "web", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied.Spec.Options.KeyAlgorithm).To(Equal(certificatev1beta1.CertificateKeyAlgorithmECPrime256v1))
+		data, err := json.Marshal(ApplyConfigurationFromCertificate(applied).Spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`"options":{"keyAlgorithm":"EC_prime256v1"}`))
+		Expect(string(data)).NotTo(ContainSubstring("validation"))
+	})
+
+	It("Should set the options on the generated certificates", func() {
+		ctx := context.Background()
+		ingress := newIngress("web", "default")
+		ingress.Annotations[IngressKeyAlgorithmKey] = string(certificatev1beta1.CertificateKeyAlgorithmECPrime256v1)
+		ingress.Annotations[IngressRegionKey] = "ca-central-1"
+		ingress.Annotations[IngressTagsKey] = "team=web, env=prod"
+		ingress.Annotations[IngressDeletionPolicyKey] = string(certificatev1beta1.CertificateDeletionPolicyRetain)
+		ingress.Annotations[IngressValidationMethodKey] = string(certificatev1beta1.CertificateValidationMethodDNS)
+		ingress.Annotations[IngressExtraSANsKey] = "www.test.local"
+		recorder := record.NewFakeRecorder(10)
+		r := &IngressReconciler{
+			Client:   newFakeClient(ingress),
+			Scheme:   testScheme,
+			recorder: recorder,
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "web", Namespace: "default"}}
+
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		cert := &certificatev1beta1.Certificate{}
+		Expect(r.Get(ctx, request.NamespacedName, cert)).To(Succeed())
+		Expect(cert.Spec.SubjectAlternativeNames).To(Equal([]string{"test.local", "www.test.local"}))
+		Expect(cert.Spec.Options.KeyAlgorithm).To(Equal(certificatev1beta1.CertificateKeyAlgorithmECPrime256v1))
+		Expect(cert.Spec.Region).To(Equal("ca-central-1"))
+		Expect(cert.Spec.Tags).To(Equal(map[string]string{"team": "web", "env": "prod"}))
+		Expect(cert.Spec.DeletionPolicy).To(Equal(certificatev1beta1.CertificateDeletionPolicyRetain))
+		Expect(cert.Spec.Validation.Method).To(Equal(certificatev1beta1.CertificateValidationMethodDNS))
+
+		By("reporting the invalid annotations without changing the certificate")
+		Expect(r.Get(ctx, request.NamespacedName, ingress)).To(Succeed())
+		ingress.Annotations[IngressRegionKey] = "central"
+		Expect(r.Update(ctx, ingress)).To(Succeed())
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring(IngressEventInvalidCertificateOptions)))
+		Expect(r.Get(ctx, request.NamespacedName, cert)).To(Succeed())
+		Expect(cert.Spec.Region).To(Equal("ca-central-1"))
+	})
+})
+
+var _ = Describe("Ingress hosts partition", func() {
+	table.DescribeTable("Should split the hosts in groups of the maximum size",
+		func(hosts []string, previous [][]string, expected [][]string) {
+			Expect(partitionHosts(hosts, previous, 2)).To(Equal(expected))
+		},
+		table.Entry("new ingress", []string{"a", "b", "c", "d", "e"}, nil,
+			[][]string{{"a", "b"}, {"c", "d"}, {"e"}}),
+		table.Entry("new host in the first group with room", []string{"a", "b", "c", "d", "e"},
+			[][]string{{"b", "c"}, {"d"}, {"e"}},
+			[][]string{{"b", "c"}, {"a", "d"}, {"e"}}),
+		table.Entry("new host in a new group", []string{"a", "b", "c"},
+			[][]string{{"b", "c"}},
+			[][]string{{"b", "c"}, {"a"}}),
+		table.Entry("removed hosts", []string{"a", "d"},
+			[][]string{{"b", "c"}, {"a", "d"}},
+			[][]string{nil, {"a", "d"}}),
+		table.Entry("lowered maximum", []string{"a", "b", "c"},
+			[][]string{{"a", "b", "c"}},
+			[][]string{{"a", "b"}, {"c"}}),
+	)
+})
+
 func newIngress(name, namespace string) *networkingv1.Ingress {
 	prefix := networkingv1.PathTypePrefix
 	rule := networkingv1.IngressRule{
@@ -0,0 +1,190 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+const (
+	// IngressKeyAlgorithmKey sets the key algorithm of the generated Certificates
+	IngressKeyAlgorithmKey = "acm-manager.io/key-algorithm"
+	// IngressRegionKey sets the AWS region of the generated Certificates
+	IngressRegionKey = "acm-manager.io/region"
+	// IngressTagsKey holds comma separated key=value tags of the generated Certificates
+	IngressTagsKey = "acm-manager.io/tags"
+	// IngressDeletionPolicyKey sets the deletion policy of the generated Certificates
+	IngressDeletionPolicyKey = "acm-manager.io/deletion-policy"
+	// IngressValidationMethodKey sets the validation method of the generated Certificates
+	IngressValidationMethodKey = "acm-manager.io/validation-method"
+	// IngressExtraSANsKey holds comma separated names added to the hosts of the Ingress
+	IngressExtraSANsKey = "acm-manager.io/extra-sans"
+)
+
+const IngressEventInvalidCertificateOptions = "InvalidCertificateOptions"
+
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]+$`)
+
+// ingressCertificateOptions are the spec fields of the Certificates generated
+// for an Ingress set by its annotations.
+type ingressCertificateOptions struct {
+	keyAlgorithm     certificatev1beta1.CertificateKeyAlgorithm
+	region           string
+	tags             map[string]string
+	deletionPolicy   certificatev1beta1.CertificateDeletionPolicy
+	validationMethod certificatev1beta1.CertificateValidationMethod
+}
+
+// parseIngressCertificateOptions returns the certificate options of the
+// annotations of an Ingress, the error listing all the invalid annotations.
+func parseIngressCertificateOptions(ingress *networkingv1.Ingress) (*ingressCertificateOptions, error) {
+	annotations := ingress.GetAnnotations()
+	options := &ingressCertificateOptions{
+		keyAlgorithm:     certificatev1beta1.CertificateKeyAlgorithm(annotations[IngressKeyAlgorithmKey]),
+		region:           annotations[IngressRegionKey],
+		deletionPolicy:   certificatev1beta1.CertificateDeletionPolicy(annotations[IngressDeletionPolicyKey]),
+		validationMethod: certificatev1beta1.CertificateValidationMethod(annotations[IngressValidationMethodKey]),
+	}
+
+	var errs []error
+	if !isOneOf(options.keyAlgorithm, certificatev1beta1.CertificateKeyAlgorithmRSA2048,
+		certificatev1beta1.CertificateKeyAlgorithmECPrime256v1, certificatev1beta1.CertificateKeyAlgorithmECSecp384r1) {
+		errs = append(errs, fmt.Errorf("%s: unsupported key algorithm %q", IngressKeyAlgorithmKey, options.keyAlgorithm))
+	}
+	if options.region != "" && !regionPattern.MatchString(options.region) {
+		errs = append(errs, fmt.Errorf("%s: invalid region %q", IngressRegionKey, options.region))
+	}
+	if !isOneOf(options.deletionPolicy, certificatev1beta1.CertificateDeletionPolicyDelete, certificatev1beta1.CertificateDeletionPolicyRetain) {
+		errs = append(errs, fmt.Errorf("%s: unsupported deletion policy %q", IngressDeletionPolicyKey, options.deletionPolicy))
+	}
+	if !isOneOf(options.validationMethod, certificatev1beta1.CertificateValidationMethodDNS, certificatev1beta1.CertificateValidationMethodEmail) {
+		errs = append(errs, fmt.Errorf("%s: unsupported validation method %q", IngressValidationMethodKey, options.validationMethod))
+	}
+
+	for _, tag := range hostPatterns(annotations[IngressTagsKey]) {
+		key, value, _ := strings.Cut(tag, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch {
+		case key == "":
+			errs = append(errs, fmt.Errorf("%s: tag %q is not key=value", IngressTagsKey, tag))
+		case strings.HasPrefix(key, TagReservedPrefix):
+			errs = append(errs, fmt.Errorf("%s: tags starting with %s are reserved", IngressTagsKey, TagReservedPrefix))
+		default:
+			if options.tags == nil {
+				options.tags = map[string]string{}
+			}
+			options.tags[key] = value
+		}
+	}
+
+	if _, err := ingressExtraSANs(ingress); err != nil {
+		errs = append(errs, err)
+	}
+
+	return options, errors.Join(errs...)
+}
+
+// applyTo sets the options on the spec of a generated Certificate. The fields
+// of the annotations that are not set are cleared, the Ingress being the
+// source of the spec.
+func (o *ingressCertificateOptions) applyTo(spec *certificatev1beta1.CertificateSpec) {
+	spec.Region = o.region
+	spec.Tags = o.tags
+	spec.DeletionPolicy = o.deletionPolicy
+
+	spec.Options = nil
+	if o.keyAlgorithm != "" {
+		spec.Options = &certificatev1beta1.CertificateOptions{KeyAlgorithm: o.keyAlgorithm}
+	}
+	spec.Validation = nil
+	if o.validationMethod != "" {
+		spec.Validation = &certificatev1beta1.CertificateValidation{Method: o.validationMethod}
+	}
+}
+
+// merge sets the options of another Ingress of a group that are not set yet,
+// and returns the annotations the Ingress sets to another value.
+func (o *ingressCertificateOptions) merge(other *ingressCertificateOptions) []string {
+	var conflicts []string
+	conflicts = mergeOption(&o.keyAlgorithm, other.keyAlgorithm, IngressKeyAlgorithmKey, conflicts)
+	conflicts = mergeOption(&o.region, other.region, IngressRegionKey, conflicts)
+	conflicts = mergeOption(&o.deletionPolicy, other.deletionPolicy, IngressDeletionPolicyKey, conflicts)
+	conflicts = mergeOption(&o.validationMethod, other.validationMethod, IngressValidationMethodKey, conflicts)
+
+	tagConflict := false
+	for key, value := range other.tags {
+		if current, ok := o.tags[key]; ok {
+			tagConflict = tagConflict || current != value
+			continue
+		}
+		if o.tags == nil {
+			o.tags = map[string]string{}
+		}
+		o.tags[key] = value
+	}
+	if tagConflict {
+		conflicts = append(conflicts, IngressTagsKey)
+	}
+	return conflicts
+}
+
+// mergeOption sets an option that is not set yet. The annotation key is added
+// to the conflicts when the option is already set to another value.
+func mergeOption[T comparable](option *T, value T, key string, conflicts []string) []string {
+	var zero T
+	switch {
+	case value == zero || *option == value:
+	case *option == zero:
+		*option = value
+	default:
+		conflicts = append(conflicts, key)
+	}
+	return conflicts
+}
+
+// ingressExtraSANs returns the valid names of the extra SANs annotation of an
+// Ingress, the error listing the invalid ones.
+func ingressExtraSANs(ingress *networkingv1.Ingress) ([]string, error) {
+	var sans, invalid []string
+	for _, san := range hostPatterns(ingress.GetAnnotations()[IngressExtraSANsKey]) {
+		name, wildcard := strings.CutPrefix(san, "*.")
+		if len(validation.IsDNS1123Subdomain(strings.ToLower(name))) > 0 ||
+			strings.Contains(name, "*") || wildcard && !strings.Contains(name, ".") {
+			invalid = append(invalid, san)
+			continue
+		}
+		sans = append(sans, san)
+	}
+	if len(invalid) > 0 {
+		return sans, fmt.Errorf("%s: invalid names %s", IngressExtraSANsKey, strings.Join(invalid, ", "))
+	}
+	return sans, nil
+}
+
+// isOneOf returns true when the value is empty or one of the allowed values.
+func isOneOf[T ~string](value T, allowed ...T) bool {
+	return value == "" || slices.Contains(allowed, value)
+}
@@ -0,0 +1,399 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+	certificateclient "vdesjardins/acm-manager/pkg/client/versioned"
+	"vdesjardins/acm-manager/pkg/policy"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// IngressGroupNameKey is the annotation of the AWS Load Balancer Controller
+	// grouping Ingresses on a single ALB
+	IngressGroupNameKey = "alb.ingress.kubernetes.io/group.name"
+
+	// IngressGroupLabel is set on the Certificates of an IngressGroup to the
+	// name of the group
+	IngressGroupLabel = "acm-manager.io/ingress-group"
+)
+
+// IngressGroupNamespace is the namespace of the Certificates shared by the
+// Ingresses of an IngressGroup. Empty disables the shared Certificates, each
+// Ingress of a group getting its own.
+var IngressGroupNamespace = ""
+
+// IngressGroupReconciler generates the Certificates of the hosts of all the
+// Ingresses of an ALB IngressGroup and sets their ARNs on every member. The
+// requests are named after the group.
+type IngressGroupReconciler struct {
+	client.Client
+	certClient certificateclient.Interface
+	Scheme     *runtime.Scheme
+
+	// Policy leaves out the hosts of the members their namespace can not
+	// request, the group Certificates being checked against the
+	// IngressGroupNamespace only. Nil allows any host.
+	Policy *policy.Checker
+}
+
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=acm-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile computes the Certificates of an IngressGroup from the union of the
+// hosts of its members.
+func (r *IngressGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx).WithValues("group", req.Name)
+
+	members, err := r.groupMembers(ctx, req.Name)
+	if err != nil {
+		log.Error(err, "unable to list ingresses of group")
+		return ctrl.Result{}, err
+	}
+	className, options, err := r.groupCertificateSettings(ctx, members)
+	if err != nil {
+		log.Error(err, "unable to update ingress conditions")
+		return ctrl.Result{}, err
+	}
+
+	hostMap := map[string]bool{}
+	for i := range members {
+		memberHosts, err := r.allowedHosts(ctx, &members[i])
+		if err != nil {
+			log.Error(err, "unable to check hosts of ingress", "ingress", members[i].Namespace+"/"+members[i].Name)
+			return ctrl.Result{}, err
+		}
+		for _, h := range memberHosts {
+			hostMap[h] = true
+		}
+	}
+	hosts := make([]string, 0, len(hostMap))
+	for h := range hostMap {
+		hosts = append(hosts, h)
+	}
+	slices.Sort(hosts)
+
+	certs := &certificatev1beta1.CertificateList{}
+	if err := r.List(ctx, certs, client.InNamespace(IngressGroupNamespace), client.MatchingLabels{IngressGroupLabel: req.Name}); err != nil {
+		log.Error(err, "unable to list certificates of group")
+		return ctrl.Result{}, err
+	}
+	owned := certs.Items
+	sortCertificatesByName(owned)
+
+	// the ARNs are removed from the former members before their certificates
+	// can be deleted, ACM refusing to delete a certificate in use
+	if err := r.releaseFormerMembers(ctx, members, owned); err != nil {
+		log.Error(err, "unable to remove certificate arns of group from former ingresses")
+		return ctrl.Result{}, err
+	}
+
+	previous := make([][]string, len(owned))
+	for i := range owned {
+		previous[i] = owned[i].Spec.SubjectAlternativeNames
+	}
+	// the certificates of a group without members or hosts are all removed
+	groups := partitionHosts(hosts, previous, IngressMaxCertificateHosts)
+
+	var arns []string
+	issued := true
+	for i, group := range groups {
+		newCert := i >= len(owned)
+		cert := &certificatev1beta1.Certificate{}
+		if newCert {
+			cert.Name = partitionCertificateName(ingressGroupCertificateName(req.Name), owned)
+			cert.Namespace = IngressGroupNamespace
+			cert.Labels = map[string]string{IngressGroupLabel: req.Name}
+		} else {
+			cert = &owned[i]
+		}
+
+		if len(group) == 0 {
+			if err := r.Delete(ctx, cert); client.IgnoreNotFound(err) != nil {
+				log.Error(err, "unable to delete certificate of group", "certificate", cert.Name)
+				return ctrl.Result{}, err
+			}
+			log.Info("certificate without hosts deleted", "certificate", cert.Name)
+			continue
+		}
+
+		cert.Spec.CommonName = group[0]
+		cert.Spec.SubjectAlternativeNames = group
+		cert.Spec.CertificateClassName = className
+		options.applyTo(&cert.Spec)
+
+		if newCert {
+			if err := r.Create(ctx, cert); err != nil {
+				log.Error(err, "unable to create certificate for group", "certificate", cert.Name)
+				return ctrl.Result{}, err
+			}
+			owned = append(owned, *cert)
+			log.Info("certificate created", "certificate", cert.Name)
+		} else {
+			if err := updateCertificate(ctx, r.certClient, cert); err != nil {
+				log.Error(err, "unable to update certificate for group", "certificate", cert.Name)
+				return ctrl.Result{}, err
+			}
+			log.Info("certificate updated from group ingresses", "certificate", cert.Name)
+		}
+
+		if cert.Status.CertificateArn == "" || cert.Status.Status != certificatev1beta1.CertificateStatusIssued {
+			issued = false
+		}
+		arns = append(arns, cert.Status.CertificateArn)
+	}
+
+	// update the members with the certificate ARNs once all issued. if not requeue
+	if len(members) == 0 {
+		return ctrl.Result{}, nil
+	}
+	if !issued {
+		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
+	}
+	for _, ingress := range members {
+		annotations, changed := certificateArnAnnotations(&ingress, arns)
+		if !changed {
+			continue
+		}
+		if err := applyCertificateArnAnnotations(ctx, r.Client, &ingress, annotations); err != nil {
+			log.Error(err, "unable to update ingress with certificate arn", "ingress", ingress.Namespace+"/"+ingress.Name)
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// groupCertificateSettings returns the class and options of the Certificates of
+// a group, merged from the annotations of its members. An option keeps the
+// value of the first member setting it, by namespace and name. The members
+// setting another value, or invalid options that are ignored, are reported as
+// a condition of the Ingress.
+func (r *IngressGroupReconciler) groupCertificateSettings(ctx context.Context, members []networkingv1.Ingress) (string, *ingressCertificateOptions, error) {
+	className := ""
+	options := &ingressCertificateOptions{}
+	for i := range members {
+		ingress := &members[i]
+		condition := metav1.Condition{
+			Type:   IngressConditionGroupOptionsApplied,
+			Status: metav1.ConditionTrue,
+			Reason: IngressReasonGroupOptionsApplied,
+		}
+
+		conflicts := mergeOption(&className, ingress.GetAnnotations()[IngressCertificateClassKey], IngressCertificateClassKey, nil)
+		memberOptions, err := parseIngressCertificateOptions(ingress)
+		if err == nil {
+			conflicts = append(conflicts, options.merge(memberOptions)...)
+		}
+		switch {
+		case err != nil:
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = IngressReasonGroupOptionsInvalid
+			condition.Message = fmt.Sprintf("certificate options of the ingress are ignored: %s", err)
+		case len(conflicts) > 0:
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = IngressReasonGroupOptionsConflict
+			condition.Message = fmt.Sprintf("%s differ from another ingress of the group, the value of the first ingress by namespace and name is used",
+				strings.Join(conflicts, ", "))
+		}
+
+		if condition.Status == metav1.ConditionTrue && ingressCondition(ingress, IngressConditionGroupOptionsApplied) == nil {
+			continue
+		}
+		if err := setIngressCondition(ctx, r.Client, ingress, condition); err != nil {
+			return "", nil, err
+		}
+	}
+	return className, options, nil
+}
+
+// allowedHosts returns the hosts of a member of a group the DomainPolicies of
+// its namespace allow. The denied hosts are reported as a condition of the
+// Ingress.
+func (r *IngressGroupReconciler) allowedHosts(ctx context.Context, ingress *networkingv1.Ingress) ([]string, error) {
+	hosts := IngressHosts(ingress)
+	if r.Policy == nil {
+		return hosts, nil
+	}
+	denied, err := r.Policy.DeniedDomains(ctx, ingress.Namespace, hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(denied) == 0 {
+		if ingressCondition(ingress, IngressConditionDomainsAllowed) == nil {
+			return hosts, nil
+		}
+		return hosts, setIngressCondition(ctx, r.Client, ingress, metav1.Condition{
+			Type:   IngressConditionDomainsAllowed,
+			Status: metav1.ConditionTrue,
+			Reason: IngressReasonDomainsAllowed,
+		})
+	}
+	err = setIngressCondition(ctx, r.Client, ingress, metav1.Condition{
+		Type:   IngressConditionDomainsAllowed,
+		Status: metav1.ConditionFalse,
+		Reason: IngressReasonDomainsDenied,
+		Message: fmt.Sprintf("hosts %s are left out of the certificates of the group, the domain policies of namespace %s not allowing them",
+			strings.Join(denied, ", "), ingress.Namespace),
+	})
+	return slices.DeleteFunc(hosts, func(h string) bool { return slices.Contains(denied, h) }), err
+}
+
+// releaseFormerMembers removes the ARNs of the Certificates of a group from the
+// managed ARNs of the Ingresses that are no longer members of the group.
+func (r *IngressGroupReconciler) releaseFormerMembers(ctx context.Context, members []networkingv1.Ingress, certs []certificatev1beta1.Certificate) error {
+	var groupArns []string
+	for i := range certs {
+		if arn := certs[i].Status.CertificateArn; arn != "" {
+			groupArns = append(groupArns, arn)
+		}
+	}
+	if len(groupArns) == 0 {
+		return nil
+	}
+
+	ingresses := &networkingv1.IngressList{}
+	if err := r.List(ctx, ingresses); err != nil {
+		return err
+	}
+	for i := range ingresses.Items {
+		ingress := &ingresses.Items[i]
+		if slices.ContainsFunc(members, func(m networkingv1.Ingress) bool {
+			return m.Namespace == ingress.Namespace && m.Name == ingress.Name
+		}) {
+			continue
+		}
+		managed := splitArns(ingress.GetAnnotations()[IngressManagedCertificateArnsKey])
+		remaining := slices.DeleteFunc(slices.Clone(managed), func(arn string) bool { return slices.Contains(groupArns, arn) })
+		if len(remaining) == len(managed) {
+			continue
+		}
+		annotations, _ := certificateArnAnnotations(ingress, remaining)
+		if err := applyCertificateArnAnnotations(ctx, r.Client, ingress, annotations); err != nil {
+			return fmt.Errorf("unable to update ingress %s/%s: %w", ingress.Namespace, ingress.Name, err)
+		}
+		log.FromContext(ctx).Info("certificate arns of group removed from former ingress", "ingress", ingress.Namespace+"/"+ingress.Name)
+	}
+	return nil
+}
+
+// groupMembers returns the Ingresses of a group a Certificate is generated for,
+// the ones referencing a Certificate left out, sorted by namespace and name.
+func (r *IngressGroupReconciler) groupMembers(ctx context.Context, group string) ([]networkingv1.Ingress, error) {
+	ingresses := &networkingv1.IngressList{}
+	if err := r.List(ctx, ingresses); err != nil {
+		return nil, err
+	}
+
+	var members []networkingv1.Ingress
+	for _, ingress := range ingresses.Items {
+		if ingressGroupName(&ingress) != group || !ingress.DeletionTimestamp.IsZero() ||
+			ingress.GetAnnotations()[IngressCertificateRefKey] != "" {
+			continue
+		}
+		shouldCreate, err := IsIngressShouldCreateCert(ctx, r.Client, &ingress)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve class of ingress %s/%s: %w", ingress.Namespace, ingress.Name, err)
+		}
+		if shouldCreate {
+			members = append(members, ingress)
+		}
+	}
+	slices.SortFunc(members, func(a, b networkingv1.Ingress) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	return members, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IngressGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	var err error
+	r.certClient, err = certificateclient.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		log.FromContext(context.TODO()).Error(err, "unable to initialize certificate client")
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("ingressgroup").
+		// the old group of an updated Ingress is enqueued too, so the hosts of
+		// the Ingresses leaving a group are removed
+		Watches(&networkingv1.Ingress{}, handler.EnqueueRequestsFromMapFunc(ingressGroupRequests)).
+		Watches(&certificatev1beta1.DomainPolicy{}, handler.EnqueueRequestsFromMapFunc(r.groupsForPolicy)).
+		Watches(&certificatev1beta1.Certificate{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+			if group := obj.GetLabels()[IngressGroupLabel]; group != "" && obj.GetNamespace() == IngressGroupNamespace {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: group}}}
+			}
+			return nil
+		})).
+		Complete(r)
+}
+
+// groupsForPolicy maps a DomainPolicy to every IngressGroup, so the hosts it
+// allows or denies are added to or removed from the group Certificates.
+func (r *IngressGroupReconciler) groupsForPolicy(ctx context.Context, obj client.Object) []reconcile.Request {
+	ingresses := &networkingv1.IngressList{}
+	if err := r.List(ctx, ingresses); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list ingresses of domain policy", "policy", obj.GetName())
+		return nil
+	}
+
+	groups := map[string]bool{}
+	var requests []reconcile.Request
+	for i := range ingresses.Items {
+		if group := ingressGroupName(&ingresses.Items[i]); group != "" && !groups[group] {
+			groups[group] = true
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: group}})
+		}
+	}
+	return requests
+}
+
+func ingressGroupRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	if group := ingressGroupName(obj.(*networkingv1.Ingress)); group != "" {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: group}}}
+	}
+	return nil
+}
+
+// ingressGroupName returns the IngressGroup of an Ingress, empty when it has none.
+func ingressGroupName(ingress *networkingv1.Ingress) string {
+	return ingress.GetAnnotations()[IngressGroupNameKey]
+}
+
+// ingressGroupCertificateName returns the name of the first Certificate of an
+// IngressGroup.
+func ingressGroupCertificateName(group string) string {
+	return "ingress-group-" + group
+}
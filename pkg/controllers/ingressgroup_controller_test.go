@@ -0,0 +1,246 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+	certificatefake "vdesjardins/acm-manager/pkg/client/versioned/fake"
+	"vdesjardins/acm-manager/pkg/policy"
+)
+
+var _ = Describe("Ingress groups", func() {
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "shared"}}
+	certKey := types.NamespacedName{Name: "ingress-group-shared", Namespace: "acm-manager"}
+
+	newGroupIngress := func(name, namespace, group string, hosts ...string) *networkingv1.Ingress {
+		ingress := newIngress(name, namespace)
+		ingress.Annotations[IngressGroupNameKey] = group
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: hosts}}
+		return ingress
+	}
+
+	// trackCertificate adds the group Certificate created by the reconciler to
+	// the clientset it applies the updates with
+	trackCertificate := func(ctx context.Context, r *IngressGroupReconciler) {
+		cert := &certificatev1beta1.Certificate{}
+		Expect(r.Get(ctx, certKey, cert)).To(Succeed())
+		r.certClient = certificatefake.NewSimpleClientset(cert)
+	}
+
+	BeforeEach(func() {
+		IngressGroupNamespace = "acm-manager"
+	})
+
+	AfterEach(func() {
+		IngressGroupNamespace = ""
+	})
+
+	It("Should share one certificate between the ingresses of a group", func() {
+		ctx := context.Background()
+		r := &IngressGroupReconciler{Client: newFakeClient(
+			newGroupIngress("web", "team-a", "shared", "web.example.com"),
+			newGroupIngress("api", "team-b", "shared", "api.example.com", "web.example.com"),
+			newGroupIngress("other", "team-b", "other", "other.example.com"),
+		)}
+
+		result, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).NotTo(BeZero())
+
+		cert := &certificatev1beta1.Certificate{}
+		Expect(r.Get(ctx, certKey, cert)).To(Succeed())
+		Expect(cert.Labels).To(HaveKeyWithValue(IngressGroupLabel, "shared"))
+		Expect(cert.Spec.SubjectAlternativeNames).To(Equal([]string{"api.example.com", "web.example.com"}))
+
+		By("setting the certificate arn on every ingress of the group once issued")
+		cert.Status.CertificateArn = "test-arn"
+		cert.Status.Status = certificatev1beta1.CertificateStatusIssued
+		Expect(r.Status().Update(ctx, cert)).To(Succeed())
+		trackCertificate(ctx, r)
+
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		for _, key := range []types.NamespacedName{{Name: "web", Namespace: "team-a"}, {Name: "api", Namespace: "team-b"}} {
+			ingress := &networkingv1.Ingress{}
+			Expect(r.Get(ctx, key, ingress)).To(Succeed())
+			Expect(ingress.Annotations).To(HaveKeyWithValue(IngressCertificateArnKey, "test-arn"))
+		}
+		ingress := &networkingv1.Ingress{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "other", Namespace: "team-b"}, ingress)).To(Succeed())
+		Expect(ingress.Annotations).NotTo(HaveKey(IngressCertificateArnKey))
+	})
+
+	It("Should remove the hosts of the ingresses leaving the group", func() {
+		ctx := context.Background()
+		r := &IngressGroupReconciler{Client: newFakeClient(
+			newGroupIngress("web", "team-a", "shared", "web.example.com"),
+			newGroupIngress("api", "team-b", "shared", "api.example.com"),
+		)}
+
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		ingress := &networkingv1.Ingress{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "api", Namespace: "team-b"}, ingress)).To(Succeed())
+		delete(ingress.Annotations, IngressGroupNameKey)
+		Expect(r.Update(ctx, ingress)).To(Succeed())
+		trackCertificate(ctx, r)
+
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		applied, err := r.certClient.AcmmanagerV1beta1().Certificates(certKey.Namespace).Get(ctx, certKey.Name, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied.Spec.SubjectAlternativeNames).To(Equal([]string{"web.example.com"}))
+
+		By("deleting the certificate of a group without ingresses")
+		Expect(r.Delete(ctx, newGroupIngress("web", "team-a", "shared"))).To(Succeed())
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, certKey, &certificatev1beta1.Certificate{})).NotTo(Succeed())
+	})
+
+	It("Should remove the certificate arns from the ingresses leaving the group", func() {
+		ctx := context.Background()
+		r := &IngressGroupReconciler{Client: newFakeClient(
+			newGroupIngress("web", "team-a", "shared", "web.example.com"),
+			newGroupIngress("api", "team-b", "shared", "api.example.com"),
+		)}
+		webKey := types.NamespacedName{Name: "web", Namespace: "team-a"}
+		apiKey := types.NamespacedName{Name: "api", Namespace: "team-b"}
+
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		cert := &certificatev1beta1.Certificate{}
+		Expect(r.Get(ctx, certKey, cert)).To(Succeed())
+		cert.Status.CertificateArn = "test-arn"
+		cert.Status.Status = certificatev1beta1.CertificateStatusIssued
+		Expect(r.Status().Update(ctx, cert)).To(Succeed())
+		trackCertificate(ctx, r)
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		leave := func(key types.NamespacedName) {
+			ingress := &networkingv1.Ingress{}
+			Expect(r.Get(ctx, key, ingress)).To(Succeed())
+			Expect(ingress.Annotations).To(HaveKeyWithValue(IngressCertificateArnKey, "test-arn"))
+			delete(ingress.Annotations, IngressGroupNameKey)
+			Expect(r.Update(ctx, ingress)).To(Succeed())
+			_, err := r.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Get(ctx, key, ingress)).To(Succeed())
+			Expect(ingress.Annotations).NotTo(HaveKey(IngressCertificateArnKey))
+			Expect(ingress.Annotations).NotTo(HaveKey(IngressManagedCertificateArnsKey))
+		}
+		leave(apiKey)
+		ingress := &networkingv1.Ingress{}
+		Expect(r.Get(ctx, webKey, ingress)).To(Succeed())
+		Expect(ingress.Annotations).To(HaveKeyWithValue(IngressCertificateArnKey, "test-arn"))
+
+		By("removing the arns from the last ingress of the group")
+		leave(webKey)
+		Expect(r.Get(ctx, certKey, &certificatev1beta1.Certificate{})).NotTo(Succeed())
+	})
+
+	It("Should leave out the hosts the namespace of a member can not request", func() {
+		ctx := context.Background()
+		c := newFakeClient(
+			&core.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}},
+			&core.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+			&certificatev1beta1.DomainPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+				Spec: certificatev1beta1.DomainPolicySpec{
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+					AllowedDomains:    []string{"*.a.example.com"},
+				},
+			},
+			newGroupIngress("web", "team-a", "shared", "web.a.example.com", "api.example.com"),
+			newGroupIngress("api", "team-b", "shared", "api.b.example.com"),
+		)
+		r := &IngressGroupReconciler{Client: c, Policy: &policy.Checker{Reader: c}}
+
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		cert := &certificatev1beta1.Certificate{}
+		Expect(r.Get(ctx, certKey, cert)).To(Succeed())
+		Expect(cert.Spec.SubjectAlternativeNames).To(Equal([]string{"api.b.example.com", "web.a.example.com"}))
+
+		ingress := &networkingv1.Ingress{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "web", Namespace: "team-a"}, ingress)).To(Succeed())
+		condition := ingressCondition(ingress, IngressConditionDomainsAllowed)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("api.example.com"))
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: "api", Namespace: "team-b"}, ingress)).To(Succeed())
+		Expect(ingressCondition(ingress, IngressConditionDomainsAllowed)).To(BeNil())
+	})
+
+	It("Should set the class and options of all the members on the certificates", func() {
+		ctx := context.Background()
+		web := newGroupIngress("web", "team-a", "shared", "web.example.com")
+		web.Annotations[IngressRegionKey] = "ca-central-1"
+		web.Annotations[IngressTagsKey] = "team=web"
+		api := newGroupIngress("api", "team-b", "shared", "api.example.com")
+		api.Annotations[IngressCertificateClassKey] = "public"
+		api.Annotations[IngressKeyAlgorithmKey] = string(certificatev1beta1.CertificateKeyAlgorithmECPrime256v1)
+		api.Annotations[IngressTagsKey] = "env=prod"
+		r := &IngressGroupReconciler{Client: newFakeClient(web, api)}
+
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		cert := &certificatev1beta1.Certificate{}
+		Expect(r.Get(ctx, certKey, cert)).To(Succeed())
+		Expect(cert.Spec.CertificateClassName).To(Equal("public"))
+		Expect(cert.Spec.Region).To(Equal("ca-central-1"))
+		Expect(cert.Spec.Options.KeyAlgorithm).To(Equal(certificatev1beta1.CertificateKeyAlgorithmECPrime256v1))
+		Expect(cert.Spec.Tags).To(Equal(map[string]string{"team": "web", "env": "prod"}))
+
+		By("keeping the values of the first member and reporting the other members setting another value")
+		ingress := &networkingv1.Ingress{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "web", Namespace: "team-a"}, ingress)).To(Succeed())
+		ingress.Annotations[IngressRegionKey] = "us-east-1"
+		ingress.Annotations[IngressCertificateClassKey] = "private"
+		Expect(r.Update(ctx, ingress)).To(Succeed())
+		trackCertificate(ctx, r)
+
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		applied, err := r.certClient.AcmmanagerV1beta1().Certificates(certKey.Namespace).Get(ctx, certKey.Name, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied.Spec.CertificateClassName).To(Equal("private"))
+		Expect(applied.Spec.Region).To(Equal("us-east-1"))
+
+		Expect(r.Get(ctx, types.NamespacedName{Name: "web", Namespace: "team-a"}, ingress)).To(Succeed())
+		Expect(ingressCondition(ingress, IngressConditionGroupOptionsApplied)).To(BeNil())
+		Expect(r.Get(ctx, types.NamespacedName{Name: "api", Namespace: "team-b"}, ingress)).To(Succeed())
+		condition := ingressCondition(ingress, IngressConditionGroupOptionsApplied)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal(IngressReasonGroupOptionsConflict))
+		Expect(condition.Message).To(ContainSubstring(IngressCertificateClassKey))
+	})
+})
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsNamespace = "acm_manager"
+
+var (
+	cleanupRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "cleanup",
+		Name:      "runs_total",
+		Help:      "Number of orphan ACM certificate cleanup runs by result.",
+	}, []string{"result"})
+
+	cleanupRunDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "cleanup",
+		Name:      "run_duration_seconds",
+		Help:      "Duration of orphan ACM certificate cleanup runs.",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 10),
+	})
+
+	cleanupLastRunTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "cleanup",
+		Name:      "last_run_timestamp_seconds",
+		Help:      "Unix time of the last completed orphan ACM certificate cleanup run.",
+	})
+
+	cleanupCertificates = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "cleanup",
+		Name:      "certificates",
+		Help:      "Number of ACM certificates by state during the last cleanup run (scanned, owned, orphaned, deleted, failed).",
+	}, []string{"state"})
+
+	cleanupDeletedCertificatesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "cleanup",
+		Name:      "deleted_certificates_total",
+		Help:      "Number of orphaned ACM certificates deleted by the cleanup job.",
+	})
+
+	requestBudgetUsed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "request_budget",
+		Name:      "used",
+		Help:      "Number of ACM certificates requested in the current budget window by account and region.",
+	}, []string{"account", "region"})
+
+	requestBudgetLimit = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "request_budget",
+		Name:      "limit",
+		Help:      "Number of ACM certificates that can be requested in a budget window by account and region.",
+	}, []string{"account", "region"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		cleanupRunsTotal,
+		cleanupRunDuration,
+		cleanupLastRunTimestamp,
+		cleanupCertificates,
+		cleanupDeletedCertificatesTotal,
+		requestBudgetUsed,
+		requestBudgetLimit,
+	)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+	"vdesjardins/acm-manager/pkg/controllers/external_api_clients"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acmpca"
+	pcatypes "github.com/aws/aws-sdk-go-v2/service/acmpca/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IssuedCertificateARNAnnotation records on a signing request the ARN of the
+// certificate issued by the private CA, so it is only issued once.
+const IssuedCertificateARNAnnotation = "acm-manager.io/certificate-arn"
+
+// privateCAPollInterval is the delay before getting a certificate the private
+// CA is still issuing
+const privateCAPollInterval = time.Second * 5
+
+// privateCASigner signs the certificate signing requests of Kubernetes objects
+// with an ACM Private CA. The certificates are issued by the private CA itself
+// and do not appear in ACM: ACM only manages the certificates whose private key
+// it holds, while the key of a signing request stays with its requester.
+type privateCASigner struct {
+	client.Client
+	PCA   external_api_clients.PcaAWSAPI
+	CAARN string
+}
+
+// sign issues the certificate of a PEM encoded CSR, once per object, and
+// returns the certificate followed by its intermediates, and the root CA. A
+// nil certificate is returned while the private CA is issuing it.
+func (s *privateCASigner) sign(ctx context.Context, obj client.Object, csr []byte, duration time.Duration) ([]byte, []byte, error) {
+	arn := obj.GetAnnotations()[IssuedCertificateARNAnnotation]
+	if arn == "" {
+		input, err := s.newIssueCertificateInput(ctx, obj, csr, duration)
+		if err != nil {
+			return nil, nil, err
+		}
+		output, err := s.PCA.IssueCertificate(ctx, input)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to issue certificate: %w", err)
+		}
+		arn = *output.CertificateArn
+
+		base := obj.DeepCopyObject().(client.Object)
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[IssuedCertificateARNAnnotation] = arn
+		obj.SetAnnotations(annotations)
+		if err := s.Patch(ctx, obj, client.MergeFrom(base)); err != nil {
+			return nil, nil, fmt.Errorf("unable to record certificate arn: %w", err)
+		}
+	}
+
+	output, err := s.PCA.GetCertificate(ctx, &acmpca.GetCertificateInput{
+		CertificateArn:          aws.String(arn),
+		CertificateAuthorityArn: aws.String(s.CAARN),
+	})
+	var inProgressErr *pcatypes.RequestInProgressException
+	if errors.As(err, &inProgressErr) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get certificate %s: %w", arn, err)
+	}
+
+	cert, ca := splitCertificateChain(aws.ToString(output.Certificate), aws.ToString(output.CertificateChain))
+	return cert, ca, nil
+}
+
+// newIssueCertificateInput returns the private CA request signing a CSR for a
+// duration. The UID of the object makes the request idempotent.
+func (s *privateCASigner) newIssueCertificateInput(ctx context.Context, obj client.Object, csr []byte, duration time.Duration) (*acmpca.IssueCertificateInput, error) {
+	if err := validateCSR(csr); err != nil {
+		return nil, err
+	}
+
+	// the signing algorithm must be the one of the CA key
+	ca, err := s.PCA.DescribeCertificateAuthority(ctx, &acmpca.DescribeCertificateAuthorityInput{
+		CertificateAuthorityArn: aws.String(s.CAARN),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe private CA %s: %w", s.CAARN, err)
+	}
+
+	return &acmpca.IssueCertificateInput{
+		CertificateAuthorityArn: aws.String(s.CAARN),
+		Csr:                     csr,
+		SigningAlgorithm:        ca.CertificateAuthority.CertificateAuthorityConfiguration.SigningAlgorithm,
+		Validity: &pcatypes.Validity{
+			Type:  pcatypes.ValidityPeriodTypeAbsolute,
+			Value: aws.Int64(time.Now().Add(duration).Unix()),
+		},
+		IdempotencyToken: aws.String(string(obj.GetUID())),
+	}, nil
+}
+
+// InvalidCSRError is returned when a certificate signing request can not be
+// parsed. Signing it again does not help.
+type InvalidCSRError struct {
+	Message string
+}
+
+func (e *InvalidCSRError) Error() string {
+	return e.Message
+}
+
+func validateCSR(csr []byte) error {
+	block, _ := pem.Decode(csr)
+	if block == nil {
+		return &InvalidCSRError{Message: "the certificate signing request is not PEM encoded"}
+	}
+	if _, err := x509.ParseCertificateRequest(block.Bytes); err != nil {
+		return &InvalidCSRError{Message: fmt.Sprintf("invalid certificate signing request: %v", err)}
+	}
+	return nil
+}
+
+// splitCertificateChain returns the certificate followed by its intermediates,
+// and the root CA, which is the last certificate of the chain.
+func splitCertificateChain(certificate, chain string) ([]byte, []byte) {
+	var blocks [][]byte
+	rest := []byte(chain)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, pem.EncodeToMemory(block))
+	}
+
+	cert := []byte(certificate)
+	if len(cert) > 0 && cert[len(cert)-1] != '\n' {
+		cert = append(cert, '\n')
+	}
+	if len(blocks) == 0 {
+		return cert, nil
+	}
+	for _, b := range blocks[:len(blocks)-1] {
+		cert = append(cert, b...)
+	}
+	return cert, blocks[len(blocks)-1]
+}
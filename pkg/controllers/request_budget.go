@@ -0,0 +1,231 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+	"vdesjardins/acm-manager/pkg/controllers/external_api_clients"
+
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RequestBudgetExceededError is returned when requesting a new ACM certificate
+// would get the account too close to its yearly ACM quota in a region.
+type RequestBudgetExceededError struct {
+	Account    string
+	Region     string
+	Used       int
+	Max        int
+	RetryAfter time.Duration
+}
+
+func (e *RequestBudgetExceededError) Error() string {
+	return fmt.Sprintf("ACM request budget exceeded for account %s in %s: %d of %d certificates requested in the current window, retry after %s",
+		e.Account, e.Region, e.Used, e.Max, e.RetryAfter.Round(time.Minute))
+}
+
+// RequestBudget limits the number of ACM certificates requested per AWS account
+// and region over a rolling window, the ACM quota being per region. Requests are recorded in a ConfigMap so the count
+// survives restarts and leader changes.
+type RequestBudget struct {
+	client.Client
+	// Reader is used to read the ConfigMap without caching all ConfigMaps of the cluster
+	Reader    client.Reader
+	STSClient external_api_clients.StsAWSAPI
+
+	// Namespace and Name of the ConfigMap holding the request timestamps
+	Namespace string
+	Name      string
+
+	// Region is the region of the requests made without an explicit region
+	Region string
+
+	// Limit is the number of certificates the account can request in a region during Window
+	Limit int
+	// Threshold is the fraction of Limit after which new requests are refused
+	Threshold float64
+	// Window is the rolling window used to count requests
+	Window time.Duration
+
+	mu      sync.Mutex
+	account string
+}
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update
+
+// CallerAccount returns the AWS account the controller credentials belong to.
+func (b *RequestBudget) CallerAccount(ctx context.Context) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.account != "" {
+		return b.account, nil
+	}
+
+	output, err := b.STSClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", fmt.Errorf("unable to retrieve caller identity: %w", err)
+	}
+	b.account = *output.Account
+
+	return b.account, nil
+}
+
+// Check returns a RequestBudgetExceededError if a new certificate can not be
+// requested for the account in the region. Check and Record are not atomic:
+// only the Certificate controller of the elected leader requests certificates,
+// one Certificate at a time, and the threshold absorbs a request recorded
+// between the two calls.
+func (b *RequestBudget) Check(ctx context.Context, account, region string) error {
+	_, requests, err := b.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	region = b.region(region)
+	now := time.Now()
+	used := b.prune(requests[budgetKey(account, region)], now)
+	b.observe(account, region, len(used))
+
+	if len(used) < b.max() {
+		return nil
+	}
+
+	// the budget frees up when the oldest request leaves the window
+	retryAfter := time.Minute
+	if len(used) > 0 {
+		retryAfter = max(time.Unix(used[0], 0).Add(b.Window).Sub(now), retryAfter)
+	}
+
+	return &RequestBudgetExceededError{
+		Account:    account,
+		Region:     region,
+		Used:       len(used),
+		Max:        b.max(),
+		RetryAfter: retryAfter,
+	}
+}
+
+// Record adds a certificate request to the account budget of the region.
+func (b *RequestBudget) Record(ctx context.Context, account, region string) error {
+	region = b.region(region)
+	key := budgetKey(account, region)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, requests, err := b.load(ctx)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		used := append(b.prune(requests[key], now), now.Unix())
+
+		data, err := json.Marshal(used)
+		if err != nil {
+			return fmt.Errorf("unable to encode request budget: %w", err)
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[key] = string(data)
+
+		if cm.ResourceVersion == "" {
+			err = b.Create(ctx, cm)
+		} else {
+			err = b.Update(ctx, cm)
+		}
+		if err != nil {
+			return err
+		}
+
+		b.observe(account, region, len(used))
+
+		return nil
+	})
+}
+
+func (b *RequestBudget) load(ctx context.Context) (*core.ConfigMap, map[string][]int64, error) {
+	reader := b.Reader
+	if reader == nil {
+		reader = b.Client
+	}
+
+	cm := &core.ConfigMap{}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: b.Namespace, Name: b.Name}, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("unable to load request budget: %w", err)
+		}
+		cm.Namespace = b.Namespace
+		cm.Name = b.Name
+	}
+
+	requests := make(map[string][]int64, len(cm.Data))
+	for key, value := range cm.Data {
+		var timestamps []int64
+		if err := json.Unmarshal([]byte(value), &timestamps); err != nil {
+			return nil, nil, fmt.Errorf("unable to decode request budget %s: %w", key, err)
+		}
+		requests[key] = timestamps
+	}
+
+	return cm, requests, nil
+}
+
+// prune removes the requests that are outside of the window
+func (b *RequestBudget) prune(timestamps []int64, now time.Time) []int64 {
+	start := now.Add(-b.Window).Unix()
+	result := []int64{}
+	for _, t := range timestamps {
+		if t > start {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// region returns the region of a request, the default one when empty
+func (b *RequestBudget) region(region string) string {
+	if region == "" {
+		return b.Region
+	}
+	return region
+}
+
+// budgetKey returns the ConfigMap key of an account and region, ConfigMap keys
+// can not contain a slash.
+func budgetKey(account, region string) string {
+	return account + "." + region
+}
+
+func (b *RequestBudget) max() int {
+	return int(math.Floor(float64(b.Limit) * b.Threshold))
+}
+
+func (b *RequestBudget) observe(account, region string, used int) {
+	requestBudgetUsed.WithLabelValues(account, region).Set(float64(used))
+	requestBudgetLimit.WithLabelValues(account, region).Set(float64(b.Limit))
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type stsClientMock struct {
+	calls int
+}
+
+func (s *stsClientMock) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	s.calls++
+	return &sts.GetCallerIdentityOutput{Account: aws.String("123456789012")}, nil
+}
+
+var _ = Describe("ACM request budget", func() {
+	newBudget := func(objs ...client.Object) *RequestBudget {
+		return &RequestBudget{
+			Client:    newFakeClient(objs...),
+			STSClient: &stsClientMock{},
+			Region:    "us-east-1",
+			Namespace: "acm-manager",
+			Name:      "request-budget",
+			Limit:     10,
+			Threshold: 0.5,
+			Window:    time.Hour,
+		}
+	}
+
+	It("Should cache the caller account", func() {
+		budget := newBudget()
+		stsMock := budget.STSClient.(*stsClientMock)
+
+		for i := 0; i < 2; i++ {
+			account, err := budget.CallerAccount(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(account).To(Equal("123456789012"))
+		}
+		Expect(stsMock.calls).To(Equal(1))
+	})
+
+	It("Should refuse requests once the threshold is reached", func() {
+		ctx := context.Background()
+		budget := newBudget()
+
+		for i := 0; i < 5; i++ {
+			Expect(budget.Check(ctx, "123456789012", "")).To(Succeed())
+			Expect(budget.Record(ctx, "123456789012", "")).To(Succeed())
+		}
+
+		err := budget.Check(ctx, "123456789012", "us-east-1")
+		var budgetErr *RequestBudgetExceededError
+		Expect(errors.As(err, &budgetErr)).To(BeTrue())
+		Expect(budgetErr.Used).To(Equal(5))
+		Expect(budgetErr.Region).To(Equal("us-east-1"))
+		Expect(budgetErr.RetryAfter).To(BeNumerically(">", 50*time.Minute))
+
+		By("keeping other accounts budget separate")
+		Expect(budget.Check(ctx, "210987654321", "")).To(Succeed())
+
+		By("keeping other regions budget separate")
+		Expect(budget.Check(ctx, "123456789012", "eu-west-1")).To(Succeed())
+		Expect(budget.Record(ctx, "123456789012", "eu-west-1")).To(Succeed())
+
+		By("persisting the requests in a ConfigMap")
+		cm := &core.ConfigMap{}
+		Expect(budget.Get(ctx, types.NamespacedName{Namespace: "acm-manager", Name: "request-budget"}, cm)).To(Succeed())
+		Expect(cm.Data).To(HaveKey("123456789012.us-east-1"))
+		Expect(cm.Data).To(HaveKey("123456789012.eu-west-1"))
+	})
+
+	It("Should not count requests outside of the window", func() {
+		old := time.Now().Add(-2 * time.Hour).Unix()
+		budget := newBudget(&core.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "acm-manager", Name: "request-budget"},
+			Data: map[string]string{
+				"123456789012.us-east-1": fmt.Sprintf("[%d,%d,%d,%d,%d]", old, old, old, old, old),
+			},
+		})
+
+		Expect(budget.Check(context.Background(), "123456789012", "")).To(Succeed())
+	})
+})
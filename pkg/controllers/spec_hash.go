@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+// certificateSpecHash returns a short hash identifying the fields of the
+// certificate spec a new ACM certificate is requested for when they change,
+// the ones of CertificateReplacedFields. The tags and deletion policy do not
+// change the ACM certificate and are left out. Subject alternative names are
+// sorted so their order does not matter.
+func certificateSpecHash(spec *certificatev1beta1.CertificateSpec) string {
+	var issuerRef *certificatev1beta1.IssuerReference
+	if spec.IssuerRef != nil {
+		issuerRef = &certificatev1beta1.IssuerReference{Name: spec.IssuerRef.Name, Kind: spec.IssuerRef.Kind}
+		if issuerRef.Kind == "" {
+			issuerRef.Kind = certificatev1beta1.IssuerKind
+		}
+	}
+	fields := struct {
+		CommonName                     string                                            `json:"commonName"`
+		SubjectAlternativeNames        []string                                          `json:"subjectAlternativeNames,omitempty"`
+		IssuerRef                      *certificatev1beta1.IssuerReference               `json:"issuerRef,omitempty"`
+		Region                         string                                            `json:"region,omitempty"`
+		ValidationMethod               certificatev1beta1.CertificateValidationMethod    `json:"validationMethod"`
+		KeyAlgorithm                   certificatev1beta1.CertificateKeyAlgorithm        `json:"keyAlgorithm,omitempty"`
+		CertificateTransparencyLogging certificatev1beta1.CertificateTransparencyLogging `json:"certificateTransparencyLogging,omitempty"`
+		Export                         bool                                              `json:"export,omitempty"`
+	}{
+		CommonName:                     spec.CommonName,
+		SubjectAlternativeNames:        slices.Sorted(slices.Values(spec.SubjectAlternativeNames)),
+		IssuerRef:                      issuerRef,
+		Region:                         spec.Region,
+		ValidationMethod:               spec.ValidationMethod(),
+		KeyAlgorithm:                   spec.KeyAlgorithm(),
+		CertificateTransparencyLogging: transparencyLogging(spec),
+		Export:                         exportEnabled(spec),
+	}
+
+	// marshalling a struct of strings can not fail
+	data, _ := json.Marshal(fields)
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/smithy-go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+// acmClientTagMock records the tags added to an ACM certificate
+type acmClientTagMock struct {
+	acmClientMock
+	tags map[string]string
+}
+
+func (a *acmClientTagMock) AddTagsToCertificate(ctx context.Context, params *acm.AddTagsToCertificateInput, optFns ...func(*acm.Options)) (*acm.AddTagsToCertificateOutput, error) {
+	if a.tags == nil {
+		a.tags = map[string]string{}
+	}
+	for _, t := range params.Tags {
+		a.tags[*t.Key] = *t.Value
+	}
+	return &acm.AddTagsToCertificateOutput{}, nil
+}
+
+// acmClientDeletedMock reports its certificates as deleted in ACM
+type acmClientDeletedMock struct {
+	acmClientMock
+}
+
+func (a *acmClientDeletedMock) DescribeCertificate(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+	return nil, &smithy.GenericAPIError{Code: "ResourceNotFoundException", Message: "not found"}
+}
+
+var _ = Describe("Certificate spec hash", func() {
+	newCertificate := func(sans ...string) *certificatev1beta1.Certificate {
+		return &certificatev1beta1.Certificate{
+			Spec: certificatev1beta1.CertificateSpec{
+				CommonName:              "test.example.com",
+				SubjectAlternativeNames: sans,
+			},
+		}
+	}
+
+	It("Should ignore the order of subject alternative names in the spec hash", func() {
+		a := newCertificate("a.example.com", "b.example.com")
+		b := newCertificate("b.example.com", "a.example.com")
+		c := newCertificate("a.example.com", "c.example.com")
+
+		Expect(certificateSpecHash(&a.Spec)).To(Equal(certificateSpecHash(&b.Spec)))
+		Expect(certificateSpecHash(&a.Spec)).NotTo(Equal(certificateSpecHash(&c.Spec)))
+		Expect(a.Spec.SubjectAlternativeNames).To(Equal([]string{"a.example.com", "b.example.com"}))
+	})
+
+	It("Should only hash the fields of the ACM certificate", func() {
+		a := newCertificate("a.example.com")
+		b := newCertificate("a.example.com")
+		b.Spec.Tags = map[string]string{"team": "web"}
+		b.Spec.DeletionPolicy = certificatev1beta1.CertificateDeletionPolicyRetain
+		Expect(certificateSpecHash(&a.Spec)).To(Equal(certificateSpecHash(&b.Spec)))
+
+		b.Spec.Options = &certificatev1beta1.CertificateOptions{KeyAlgorithm: certificatev1beta1.CertificateKeyAlgorithmECPrime256v1}
+		Expect(certificateSpecHash(&a.Spec)).NotTo(Equal(certificateSpecHash(&b.Spec)))
+	})
+
+	It("Should not wait when the settle window is disabled", func() {
+		r := &CertificateReconciler{}
+		cert := newCertificate("a.example.com")
+
+		Expect(r.specSettleDelay(cert)).To(BeZero())
+		Expect(cert.Status.PendingSpecHash).To(BeEmpty())
+	})
+
+	It("Should wait until the spec is stable for the whole window", func() {
+		r := &CertificateReconciler{SpecSettleWindow: time.Minute}
+		cert := newCertificate("a.example.com")
+
+		By("recording a new pending spec")
+		Expect(r.specSettleDelay(cert)).To(Equal(time.Minute))
+		Expect(cert.Status.PendingSpecHash).To(Equal(certificateSpecHash(&cert.Spec)))
+		Expect(cert.Status.PendingSpecSince).NotTo(BeNil())
+
+		By("restarting the wait when the spec changes again")
+		cert.Status.PendingSpecSince = &metav1.Time{Time: time.Now().Add(-50 * time.Second)}
+		cert.Spec.SubjectAlternativeNames = []string{"b.example.com"}
+		Expect(r.specSettleDelay(cert)).To(Equal(time.Minute))
+
+		By("acting on the spec once the window elapsed")
+		cert.Status.PendingSpecSince = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+		Expect(r.specSettleDelay(cert)).To(BeNumerically("<=", 0))
+	})
+
+	It("Should only compare with ACM when the spec changed or the verification is due", func() {
+		r := &CertificateReconciler{VerifyInterval: time.Hour}
+		cert := newCertificate("a.example.com")
+
+		By("comparing certificates that were never verified")
+		Expect(r.needsACMComparison(cert)).To(BeTrue())
+
+		By("skipping the comparison when the hash matches")
+		cert.Status.SpecHash = certificateSpecHash(&cert.Spec)
+		cert.Status.LastVerifiedTime = &metav1.Time{Time: time.Now()}
+		Expect(r.needsACMComparison(cert)).To(BeFalse())
+
+		By("comparing when the spec changed")
+		cert.Spec.SubjectAlternativeNames = []string{"b.example.com"}
+		Expect(r.needsACMComparison(cert)).To(BeTrue())
+
+		By("comparing when the verification is due")
+		cert.Status.SpecHash = certificateSpecHash(&cert.Spec)
+		cert.Status.LastVerifiedTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		Expect(r.needsACMComparison(cert)).To(BeTrue())
+	})
+
+	It("Should tell a certificate deleted in ACM from a changed one", func() {
+		ctx := context.Background()
+		cert := newCertificate("test.local")
+		cert.Spec.CommonName = "test.local"
+		cert.Status.CertificateArn = "test-arn"
+
+		r := &CertificateReconciler{ACMClient: &acmClientMock{}}
+		equals, found, err := r.compareACMCertificate(ctx, cert)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(equals).To(BeTrue())
+		Expect(found).To(BeTrue())
+
+		cert.Spec.SubjectAlternativeNames = []string{"other.local"}
+		equals, found, err = r.compareACMCertificate(ctx, cert)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(equals).To(BeFalse())
+		Expect(found).To(BeTrue())
+
+		r = &CertificateReconciler{ACMClient: &acmClientDeletedMock{}}
+		equals, found, err = r.compareACMCertificate(ctx, cert)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(equals).To(BeFalse())
+		Expect(found).To(BeFalse())
+	})
+
+	It("Should only describe the ACM certificate when its information is out of date", func() {
+		cert := newCertificate("a.example.com")
+		cert.Status.SpecHash = certificateSpecHash(&cert.Spec)
+		cert.Status.Status = certificatev1beta1.CertificateStatusPendingValidation
+		Expect(certificateInfoUpToDate(cert)).To(BeFalse())
+
+		cert.Status.Status = certificatev1beta1.CertificateStatusIssued
+		Expect(certificateInfoUpToDate(cert)).To(BeTrue())
+
+		cert.Spec.SubjectAlternativeNames = []string{"b.example.com"}
+		Expect(certificateInfoUpToDate(cert)).To(BeFalse())
+	})
+
+	It("Should update the spec hash tag of a certificate matching a changed spec", func() {
+		mock := &acmClientTagMock{}
+		r := &CertificateReconciler{ACMClient: mock}
+		cert := newCertificate("a.example.com")
+		cert.Status.CertificateArn = "test-arn"
+
+		Expect(r.updateSpecHashTag(context.Background(), cert)).To(Succeed())
+		Expect(mock.tags).To(Equal(map[string]string{TagCertificateSpecHash: certificateSpecHash(&cert.Spec)}))
+	})
+
+	It("Should tag requested certificates with the spec hash", func() {
+		cert := newCertificate("a.example.com")
+
+		input := newRequestCertificateInput(cert, nil)
+		Expect(input.Tags).To(ContainElement(SatisfyAll(
+			HaveField("Key", HaveValue(Equal(TagCertificateSpecHash))),
+			HaveField("Value", HaveValue(Equal(certificateSpecHash(&cert.Spec)))),
+		)))
+	})
+})
@@ -21,14 +21,18 @@ import (
 	"path/filepath"
 	"testing"
 
+	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -36,6 +40,7 @@ import (
 	dnsendpoint "sigs.k8s.io/external-dns/apis/v1alpha1"
 
 	certificatev1alpha1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1alpha1"
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -50,6 +55,33 @@ var (
 	cancel    context.CancelFunc
 )
 
+// testScheme holds the types of the fake clients used by the unit tests
+var testScheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(scheme.AddToScheme(testScheme))
+	utilruntime.Must(certificatev1beta1.AddToScheme(testScheme))
+	utilruntime.Must(cmapi.AddToScheme(testScheme))
+}
+
+// newFakeClient returns a fake client holding objs. The types whose status the
+// controllers update have a status subresource, like on the API server.
+func newFakeClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(objs...).
+		WithStatusSubresource(
+			&certificatev1beta1.Certificate{},
+			&certificatev1beta1.ACMIssuer{},
+			&certificatev1beta1.ClusterACMIssuer{},
+			&certificatev1beta1.TrustBundle{},
+			&certificatev1beta1.CleanupReport{},
+			&cmapi.CertificateRequest{},
+			&certificatesv1.CertificateSigningRequest{},
+		).
+		Build()
+}
+
 func TestAPIs(t *testing.T) {
 	RegisterFailHandler(Fail)
 
@@ -81,6 +113,8 @@ var _ = BeforeSuite(func() {
 
 	err = certificatev1alpha1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
+	err = certificatev1beta1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
 
 	//+kubebuilder:scaffold:scheme
 
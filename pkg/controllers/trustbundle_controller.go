@@ -0,0 +1,348 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"vdesjardins/acm-manager/pkg/controllers/external_api_clients"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/acmpca"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+const (
+	// trustBundleRefreshInterval is how often the Private CAs are read to
+	// detect a rotation
+	trustBundleRefreshInterval = time.Hour
+	// trustBundleDefaultOverlap is how long a CA certificate stays in a bundle
+	// once its Private CA stopped returning it
+	trustBundleDefaultOverlap = time.Hour * 24 * 30
+	// trustBundleDefaultKey is the ConfigMap key of the PEM bundle
+	trustBundleDefaultKey = "ca.crt"
+)
+
+// TrustBundleReconciler publishes the certificate chains of Private CAs to
+// ConfigMaps of the selected namespaces.
+type TrustBundleReconciler struct {
+	client.Client
+	Scheme  *runtime.Scheme
+	Clients external_api_clients.ClientFactory
+}
+
+//+kubebuilder:rbac:groups=acm-manager.io,resources=trustbundles,verbs=get;list;watch
+//+kubebuilder:rbac:groups=acm-manager.io,resources=trustbundles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile reads the Private CAs of a bundle and publishes their certificates.
+func (r *TrustBundleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	bundle := &certificatev1beta1.TrustBundle{}
+	if err := r.Get(ctx, req.NamespacedName, bundle); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	now := metav1.Now()
+	var current []*x509.Certificate
+	for _, source := range bundle.Spec.Sources {
+		certs, err := r.fetchSource(ctx, source)
+		if err != nil {
+			log.Error(err, "unable to read private CA certificates")
+			setTrustBundleCondition(bundle, metav1.ConditionFalse, certificatev1beta1.TrustBundleReasonFetchFailed, err.Error())
+			if err := r.Status().Update(ctx, bundle); err != nil {
+				return ctrl.Result{}, fmt.Errorf("unable to update trust bundle status: %w", err)
+			}
+			return ctrl.Result{RequeueAfter: issuerRetryInterval}, nil
+		}
+		current = append(current, certs...)
+	}
+
+	overlap := trustBundleDefaultOverlap
+	if bundle.Spec.OverlapPeriod != nil {
+		overlap = bundle.Spec.OverlapPeriod.Duration
+	}
+	bundle.Status.Certificates = mergeTrustBundleCertificates(bundle.Status.Certificates, current, now, overlap)
+
+	namespaces, err := r.publish(ctx, bundle)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	bundle.Status.Namespaces = namespaces
+	setTrustBundleCondition(bundle, metav1.ConditionTrue, certificatev1beta1.TrustBundleReasonPublished,
+		fmt.Sprintf("%d CA certificates published to %d namespaces", len(bundle.Status.Certificates), len(namespaces)))
+	if err := r.Status().Update(ctx, bundle); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to update trust bundle status: %w", err)
+	}
+
+	return ctrl.Result{RequeueAfter: trustBundleRefreshInterval}, nil
+}
+
+// fetchSource returns the certificate of a Private CA followed by its chain.
+func (r *TrustBundleReconciler) fetchSource(ctx context.Context, source certificatev1beta1.TrustBundleSource) ([]*x509.Certificate, error) {
+	caARN := source.PrivateCAARN
+	var region string
+	var role external_api_clients.Role
+	if source.ClusterACMIssuer != "" {
+		issuer, err := getIssuer(ctx, r.Client, "", &certificatev1beta1.IssuerReference{
+			Name: source.ClusterACMIssuer,
+			Kind: certificatev1beta1.ClusterIssuerKind,
+		})
+		if err != nil {
+			return nil, err
+		}
+		spec := issuer.GetSpec()
+		if spec.PrivateCAARN == "" {
+			return nil, fmt.Errorf("%s %s has no private CA", certificatev1beta1.ClusterIssuerKind, source.ClusterACMIssuer)
+		}
+		if role, err = issuerRole(issuer); err != nil {
+			return nil, err
+		}
+		caARN, region = spec.PrivateCAARN, spec.Region
+	} else {
+		parsed, err := arn.Parse(caARN)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private CA arn %s: %w", caARN, err)
+		}
+		region = parsed.Region
+	}
+
+	output, err := r.Clients.PCA(region, role).GetCertificateAuthorityCertificate(ctx, &acmpca.GetCertificateAuthorityCertificateInput{
+		CertificateAuthorityArn: aws.String(caARN),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get certificate of private CA %s: %w", caARN, err)
+	}
+
+	return parseCertificates(aws.ToString(output.Certificate) + "\n" + aws.ToString(output.CertificateChain))
+}
+
+// publish writes the bundle to the ConfigMaps of the selected namespaces and
+// deletes the ones of the namespaces no longer selected.
+func (r *TrustBundleReconciler) publish(ctx context.Context, bundle *certificatev1beta1.TrustBundle) ([]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&bundle.Spec.Target.NamespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace selector: %w", err)
+	}
+	namespaces := &core.NamespaceList{}
+	if err := r.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("unable to list namespaces: %w", err)
+	}
+
+	key := bundle.Spec.Target.Key
+	if key == "" {
+		key = trustBundleDefaultKey
+	}
+	var pemBundle strings.Builder
+	for _, c := range bundle.Status.Certificates {
+		pemBundle.WriteString(c.Certificate)
+	}
+
+	var published []string
+	for _, ns := range namespaces.Items {
+		if ns.DeletionTimestamp != nil {
+			continue
+		}
+		if err := r.syncConfigMap(ctx, bundle, ns.Name, key, pemBundle.String()); err != nil {
+			return nil, err
+		}
+		published = append(published, ns.Name)
+	}
+
+	configMaps := &core.ConfigMapList{}
+	if err := r.List(ctx, configMaps, client.MatchingLabels{certificatev1beta1.TrustBundleLabel: bundle.Name}); err != nil {
+		return nil, fmt.Errorf("unable to list trust bundle configmaps: %w", err)
+	}
+	for i := range configMaps.Items {
+		cm := &configMaps.Items[i]
+		if slices.Contains(published, cm.Namespace) && cm.Name == bundle.Spec.Target.ConfigMapName {
+			continue
+		}
+		if err := r.Delete(ctx, cm); client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("unable to delete configmap %s/%s: %w", cm.Namespace, cm.Name, err)
+		}
+	}
+
+	return published, nil
+}
+
+func (r *TrustBundleReconciler) syncConfigMap(ctx context.Context, bundle *certificatev1beta1.TrustBundle, namespace, key, data string) error {
+	cm := &core.ConfigMap{}
+	nsName := types.NamespacedName{Name: bundle.Spec.Target.ConfigMapName, Namespace: namespace}
+	newConfigMap := false
+	if err := r.Get(ctx, nsName, cm); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("unable to get configmap %s: %w", nsName, err)
+		}
+
+		newConfigMap = true
+		cm.Name = nsName.Name
+		cm.Namespace = nsName.Namespace
+		if err := ctrl.SetControllerReference(bundle, cm, r.Scheme); err != nil {
+			return fmt.Errorf("unable to set owner of configmap %s: %w", nsName, err)
+		}
+	} else if !metav1.IsControlledBy(cm, bundle) {
+		return fmt.Errorf("configmap %s is not owned by trust bundle %s", nsName, bundle.Name)
+	}
+
+	if !newConfigMap && cm.Data[key] == data && len(cm.Data) == 1 && cm.Labels[certificatev1beta1.TrustBundleLabel] == bundle.Name {
+		return nil
+	}
+	cm.Labels = labels.Merge(cm.Labels, map[string]string{certificatev1beta1.TrustBundleLabel: bundle.Name})
+	cm.Data = map[string]string{key: data}
+
+	var err error
+	if newConfigMap {
+		err = r.Create(ctx, cm)
+	} else {
+		err = r.Update(ctx, cm)
+	}
+	if apierrors.IsAlreadyExists(err) {
+		// only the ConfigMaps of the trust bundles are cached
+		return fmt.Errorf("configmap %s is not owned by trust bundle %s", nsName, bundle.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to save configmap %s: %w", nsName, err)
+	}
+
+	return nil
+}
+
+// mergeTrustBundleCertificates returns the current CA certificates followed by
+// the previous ones still in their overlap period. Expired certificates are
+// dropped.
+func mergeTrustBundleCertificates(previous []certificatev1beta1.TrustBundleCertificate, current []*x509.Certificate,
+	now metav1.Time, overlap time.Duration) []certificatev1beta1.TrustBundleCertificate {
+	var merged []certificatev1beta1.TrustBundleCertificate
+	seen := map[string]bool{}
+	for _, c := range current {
+		fingerprint := certificateFingerprint(c)
+		if seen[fingerprint] || now.Time.After(c.NotAfter) {
+			continue
+		}
+		seen[fingerprint] = true
+		merged = append(merged, certificatev1beta1.TrustBundleCertificate{
+			Fingerprint:  fingerprint,
+			Certificate:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw})),
+			NotAfter:     metav1.NewTime(c.NotAfter),
+			LastSeenTime: now,
+		})
+	}
+	for _, c := range previous {
+		if seen[c.Fingerprint] || now.Time.After(c.NotAfter.Time) || now.Time.After(c.LastSeenTime.Add(overlap)) {
+			continue
+		}
+		seen[c.Fingerprint] = true
+		merged = append(merged, c)
+	}
+	return merged
+}
+
+func parseCertificates(data string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CA certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+func certificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+func setTrustBundleCondition(bundle *certificatev1beta1.TrustBundle, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&bundle.Status.Conditions, metav1.Condition{
+		Type:               certificatev1beta1.TrustBundleConditionReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: bundle.Generation,
+	})
+}
+
+// TrustBundleConfigMapCache limits the ConfigMaps cached by the manager to the
+// ones of the trust bundles instead of every ConfigMap of the cluster.
+func TrustBundleConfigMapCache() cache.ByObject {
+	bundleLabel, err := labels.NewRequirement(certificatev1beta1.TrustBundleLabel, selection.Exists, nil)
+	utilruntime.Must(err)
+	return cache.ByObject{Label: labels.NewSelector().Add(*bundleLabel)}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TrustBundleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// the status is updated on every reconcile, the Private CAs are only
+		// read again on a spec change or once the refresh interval elapsed
+		For(&certificatev1beta1.TrustBundle{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&core.ConfigMap{}).
+		Watches(&core.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.allTrustBundles)).
+		Complete(r)
+}
+
+// allTrustBundles maps a namespace change to every TrustBundle, any of them
+// may select it.
+func (r *TrustBundleReconciler) allTrustBundles(ctx context.Context, obj client.Object) []reconcile.Request {
+	bundles := &certificatev1beta1.TrustBundleList{}
+	if err := r.List(ctx, bundles); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list trust bundles")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(bundles.Items))
+	for _, b := range bundles.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: b.Name}})
+	}
+	return requests
+}
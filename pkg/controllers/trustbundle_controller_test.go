@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+func newCACertificate(name string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour * 24 * 365),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+var _ = Describe("Trust bundles", func() {
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "internal-ca"}}
+
+	newNamespace := func(name string, labels map[string]string) *core.Namespace {
+		return &core.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	newBundle := func() *certificatev1beta1.TrustBundle {
+		return &certificatev1beta1.TrustBundle{
+			ObjectMeta: metav1.ObjectMeta{Name: "internal-ca"},
+			Spec: certificatev1beta1.TrustBundleSpec{
+				Sources: []certificatev1beta1.TrustBundleSource{{
+					PrivateCAARN: "arn:aws:acm-pca:eu-west-1:123456789012:certificate-authority/test",
+				}},
+				Target: certificatev1beta1.TrustBundleTarget{
+					ConfigMapName:     "internal-ca",
+					NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"mtls": "enabled"}},
+				},
+			},
+		}
+	}
+
+	It("Should publish the CA certificates to the selected namespaces", func() {
+		ctx := context.Background()
+		root := newCACertificate("root")
+		clients := &clientFactoryMock{pca: &pcaClientMock{caCertificate: root}}
+		r := &TrustBundleReconciler{
+			Client: newFakeClient(newBundle(),
+				newNamespace("web", map[string]string{"mtls": "enabled"}),
+				newNamespace("batch", nil)),
+			Scheme:  testScheme,
+			Clients: clients,
+		}
+
+		result, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(trustBundleRefreshInterval))
+		Expect(clients.region).To(Equal("eu-west-1"))
+
+		cm := &core.ConfigMap{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "internal-ca", Namespace: "web"}, cm)).To(Succeed())
+		Expect(cm.Data).To(Equal(map[string]string{"ca.crt": root}))
+		err = r.Get(ctx, types.NamespacedName{Name: "internal-ca", Namespace: "batch"}, cm)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		By("removing the ConfigMaps of the namespaces no longer selected")
+		ns := &core.Namespace{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "web"}, ns)).To(Succeed())
+		ns.Labels = nil
+		Expect(r.Update(ctx, ns)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		err = r.Get(ctx, types.NamespacedName{Name: "internal-ca", Namespace: "web"}, cm)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should keep the previous CA certificate during the overlap period", func() {
+		ctx := context.Background()
+		oldRoot, newRoot := newCACertificate("old root"), newCACertificate("new root")
+		pca := &pcaClientMock{caCertificate: oldRoot}
+		r := &TrustBundleReconciler{
+			Client:  newFakeClient(newBundle(), newNamespace("web", map[string]string{"mtls": "enabled"})),
+			Scheme:  testScheme,
+			Clients: &clientFactoryMock{pca: pca},
+		}
+
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		pca.caCertificate = newRoot
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		cm := &core.ConfigMap{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "internal-ca", Namespace: "web"}, cm)).To(Succeed())
+		Expect(cm.Data["ca.crt"]).To(Equal(newRoot + oldRoot))
+
+		By("dropping it once the overlap period is over")
+		bundle := &certificatev1beta1.TrustBundle{}
+		Expect(r.Get(ctx, request.NamespacedName, bundle)).To(Succeed())
+		Expect(bundle.Status.Certificates).To(HaveLen(2))
+		bundle.Status.Certificates = mergeTrustBundleCertificates(bundle.Status.Certificates, nil,
+			metav1.NewTime(time.Now().Add(trustBundleDefaultOverlap+time.Minute)), trustBundleDefaultOverlap)
+		Expect(bundle.Status.Certificates).To(BeEmpty())
+
+		Expect(strings.Count(cm.Data["ca.crt"], "BEGIN CERTIFICATE")).To(Equal(2))
+	})
+
+	It("Should only cache the ConfigMaps of the trust bundles", func() {
+		selector := TrustBundleConfigMapCache().Label
+		Expect(selector.Matches(labels.Set{certificatev1beta1.TrustBundleLabel: "internal-ca"})).To(BeTrue())
+		Expect(selector.Matches(labels.Set{"app": "web"})).To(BeFalse())
+	})
+})
@@ -0,0 +1,217 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy enforces the DomainPolicies restricting the domain names the
+// Certificates of a namespace can request.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+// DeniedError is returned when the DomainPolicies of a namespace refuse a Certificate.
+type DeniedError struct {
+	Namespace string
+	Message   string
+}
+
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("denied by the domain policies of namespace %s: %s", e.Namespace, e.Message)
+}
+
+// Checker evaluates the DomainPolicies selecting a namespace. A namespace that
+// no policy selects can request any domain name.
+type Checker struct {
+	Reader client.Reader
+}
+
+//+kubebuilder:rbac:groups=acm-manager.io,resources=domainpolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// Request is a Certificate checked against the DomainPolicies of its namespace.
+type Request struct {
+	Name    string
+	Domains []string
+}
+
+// Check returns a DeniedError if the Certificate name of the namespace can not
+// request the domain names. Certificates of the namespace created before it
+// count against the maximum number of Certificates, so existing Certificates
+// are not denied when a new one is created.
+func (c *Checker) Check(ctx context.Context, namespace, name string, domains []string) error {
+	return c.CheckAll(ctx, namespace, []Request{{Name: name, Domains: domains}})
+}
+
+// CheckAll checks several Certificates of a namespace created together, like
+// the ones generated for an Ingress. The requested Certificates that do not
+// exist yet are created after the existing ones, in order, so each counts
+// against the maximum number of Certificates of the next ones.
+func (c *Checker) CheckAll(ctx context.Context, namespace string, requests []Request) error {
+	policies, err := c.policiesFor(ctx, namespace)
+	if err != nil || len(policies) == 0 {
+		return err
+	}
+
+	for _, request := range requests {
+		for _, domain := range request.Domains {
+			if !allowedByAny(policies, domain) {
+				return &DeniedError{Namespace: namespace, Message: fmt.Sprintf("domain %s is not allowed", domain)}
+			}
+		}
+	}
+
+	max := maxCertificates(policies)
+	if max < 0 {
+		return nil
+	}
+
+	certs := &certificatev1beta1.CertificateList{}
+	if err := c.Reader.List(ctx, certs, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("unable to list certificates: %w", err)
+	}
+	// requested Certificates that do not exist yet and are created before
+	// the next ones
+	pending := 0
+	for _, request := range requests {
+		// a Certificate that does not exist yet is the newest one
+		var created *metav1.Time
+		for i := range certs.Items {
+			if certs.Items[i].Name == request.Name {
+				created = &certs.Items[i].CreationTimestamp
+			}
+		}
+		older := 0
+		for i := range certs.Items {
+			cert := &certs.Items[i]
+			if cert.Name == request.Name {
+				continue
+			}
+			if created == nil || cert.CreationTimestamp.Before(created) ||
+				cert.CreationTimestamp.Equal(created) && cert.Name < request.Name {
+				older++
+			}
+		}
+		if created == nil {
+			older += pending
+			pending++
+		}
+		if older >= max {
+			return &DeniedError{Namespace: namespace, Message: fmt.Sprintf("at most %d certificates are allowed", max)}
+		}
+	}
+
+	return nil
+}
+
+// DeniedDomains returns the domain names the DomainPolicies of the namespace
+// do not allow. The maximum number of Certificates is not checked, for domain
+// names requested by Certificates of another namespace.
+func (c *Checker) DeniedDomains(ctx context.Context, namespace string, domains []string) ([]string, error) {
+	policies, err := c.policiesFor(ctx, namespace)
+	if err != nil || len(policies) == 0 {
+		return nil, err
+	}
+
+	var denied []string
+	for _, domain := range domains {
+		if !allowedByAny(policies, domain) {
+			denied = append(denied, domain)
+		}
+	}
+	return denied, nil
+}
+
+func (c *Checker) policiesFor(ctx context.Context, namespace string) ([]certificatev1beta1.DomainPolicy, error) {
+	policies := &certificatev1beta1.DomainPolicyList{}
+	if err := c.Reader.List(ctx, policies); err != nil {
+		return nil, fmt.Errorf("unable to list domain policies: %w", err)
+	}
+	if len(policies.Items) == 0 {
+		return nil, nil
+	}
+
+	ns := &core.Namespace{}
+	if err := c.Reader.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return nil, fmt.Errorf("unable to fetch namespace %s: %w", namespace, err)
+	}
+
+	var selected []certificatev1beta1.DomainPolicy
+	for _, p := range policies.Items {
+		selector, err := metav1.LabelSelectorAsSelector(&p.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace selector of domain policy %s: %w", p.Name, err)
+		}
+		if selector.Matches(labels.Set(ns.Labels)) {
+			selected = append(selected, p)
+		}
+	}
+
+	return selected, nil
+}
+
+// Selects returns true when the policy applies to the namespace.
+func Selects(p *certificatev1beta1.DomainPolicy, ns *core.Namespace) bool {
+	selector, err := metav1.LabelSelectorAsSelector(&p.Spec.NamespaceSelector)
+	return err == nil && selector.Matches(labels.Set(ns.Labels))
+}
+
+// MatchDomain returns true when the domain name matches the pattern. A
+// *.<domain> pattern matches any name below the domain, wildcard names included.
+func MatchDomain(pattern, domain string) bool {
+	pattern = strings.TrimSuffix(strings.ToLower(pattern), ".")
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(domain, "."+suffix)
+	}
+	return domain == pattern
+}
+
+func allowedByAny(policies []certificatev1beta1.DomainPolicy, domain string) bool {
+	for _, p := range policies {
+		if slices.ContainsFunc(p.Spec.AllowedDomains, func(pattern string) bool {
+			return MatchDomain(pattern, domain)
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxCertificates returns the lowest maximum of the policies, -1 if none sets one.
+func maxCertificates(policies []certificatev1beta1.DomainPolicy) int {
+	max := -1
+	for _, p := range policies {
+		if p.Spec.MaxCertificates == nil {
+			continue
+		}
+		if m := int(*p.Spec.MaxCertificates); max < 0 || m < max {
+			max = m
+		}
+	}
+	return max
+}
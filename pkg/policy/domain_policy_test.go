@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+var _ = Describe("Domain policies", func() {
+	table.DescribeTable("Should match domain names against patterns",
+		func(pattern, domain string, match bool) {
+			Expect(MatchDomain(pattern, domain)).To(Equal(match))
+		},
+		table.Entry("exact name", "app.example.com", "app.example.com", true),
+		table.Entry("exact name is case insensitive", "app.example.com", "App.Example.com.", true),
+		table.Entry("other name", "app.example.com", "api.example.com", false),
+		table.Entry("subdomain", "*.team.example.com", "app.team.example.com", true),
+		table.Entry("deep subdomain", "*.team.example.com", "a.b.team.example.com", true),
+		table.Entry("wildcard name", "*.team.example.com", "*.team.example.com", true),
+		table.Entry("domain itself", "*.team.example.com", "team.example.com", false),
+		table.Entry("suffix of a label", "*.team.example.com", "otherteam.example.com", false),
+		table.Entry("wildcard without a dot", "*example.com", "evilexample.com", false),
+	)
+
+	newChecker := func(objs ...client.Object) *Checker {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(certificatev1beta1.AddToScheme(scheme)).To(Succeed())
+		return &Checker{Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()}
+	}
+
+	newNamespace := func(name, team string) *core.Namespace {
+		return &core.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"team": team}}}
+	}
+
+	newCertificate := func(namespace, name string, created time.Time) *certificatev1beta1.Certificate {
+		return &certificatev1beta1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, CreationTimestamp: metav1.NewTime(created)},
+		}
+	}
+
+	teamPolicy := &certificatev1beta1.DomainPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: certificatev1beta1.DomainPolicySpec{
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+			AllowedDomains:    []string{"*.web.example.com", "www.example.com"},
+			MaxCertificates:   ptr.To[int32](2),
+		},
+	}
+
+	It("Should allow any domain when no policy selects the namespace", func() {
+		checker := newChecker(teamPolicy, newNamespace("api", "api"))
+		Expect(checker.Check(context.Background(), "api", "test", []string{"api.example.com"})).To(Succeed())
+	})
+
+	It("Should only allow the domains of the policies selecting the namespace", func() {
+		ctx := context.Background()
+		checker := newChecker(teamPolicy, newNamespace("web", "web"))
+
+		Expect(checker.Check(ctx, "web", "test", []string{"app.web.example.com", "www.example.com"})).To(Succeed())
+
+		err := checker.Check(ctx, "web", "test", []string{"app.web.example.com", "api.example.com"})
+		var deniedErr *DeniedError
+		Expect(errors.As(err, &deniedErr)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("api.example.com"))
+
+		denied, err := checker.DeniedDomains(ctx, "web", []string{"app.web.example.com", "api.example.com", "www.example.com"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(denied).To(Equal([]string{"api.example.com"}))
+	})
+
+	It("Should deny the Certificates above the maximum", func() {
+		ctx := context.Background()
+		now := time.Now()
+		checker := newChecker(teamPolicy, newNamespace("web", "web"),
+			newCertificate("web", "first", now.Add(-2*time.Hour)),
+			newCertificate("web", "second", now.Add(-time.Hour)),
+			newCertificate("web", "third", now),
+		)
+		domains := []string{"app.web.example.com"}
+
+		Expect(checker.Check(ctx, "web", "first", domains)).To(Succeed())
+		Expect(checker.Check(ctx, "web", "second", domains)).To(Succeed())
+
+		var deniedErr *DeniedError
+		Expect(errors.As(checker.Check(ctx, "web", "third", domains), &deniedErr)).To(BeTrue())
+		Expect(errors.As(checker.Check(ctx, "web", "new", domains), &deniedErr)).To(BeTrue())
+	})
+
+	It("Should count the Certificates created together against the maximum", func() {
+		ctx := context.Background()
+		checker := newChecker(teamPolicy, newNamespace("web", "web"),
+			newCertificate("web", "first", time.Now()),
+		)
+		domains := []string{"app.web.example.com"}
+
+		Expect(checker.CheckAll(ctx, "web", []Request{{Name: "first", Domains: domains}, {Name: "first-2", Domains: domains}})).To(Succeed())
+
+		var deniedErr *DeniedError
+		err := checker.CheckAll(ctx, "web", []Request{
+			{Name: "first", Domains: domains}, {Name: "first-2", Domains: domains}, {Name: "first-3", Domains: domains},
+		})
+		Expect(errors.As(err, &deniedErr)).To(BeTrue())
+	})
+})
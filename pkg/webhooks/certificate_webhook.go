@@ -0,0 +1,296 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/idna"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+	"vdesjardins/acm-manager/pkg/controllers"
+	"vdesjardins/acm-manager/pkg/policy"
+)
+
+const (
+	// maxCommonNameLength is the longest common name ACM accepts
+	maxCommonNameLength = 64
+
+	// DefaultMaxSubjectAlternativeNames is the default ACM quota of domain names per certificate
+	DefaultMaxSubjectAlternativeNames = 10
+)
+
+// CertificateValidator rejects Certificates that ACM would refuse, before
+// any certificate is requested.
+type CertificateValidator struct {
+	// MaxSubjectAlternativeNames is the number of domain names, common name
+	// included, allowed in a certificate by the ACM quota of the account
+	MaxSubjectAlternativeNames int
+
+	// Policy enforces the DomainPolicies of the namespaces. Disabled when nil.
+	Policy *policy.Checker
+}
+
+// CertificateDefaulter normalizes the domain names of Certificates and fills
+// in the controller wide defaults so the stored spec is explicit.
+type CertificateDefaulter struct {
+	// KeyAlgorithm set on Certificates that do not specify one
+	KeyAlgorithm certificatev1beta1.CertificateKeyAlgorithm
+
+	// DeletionPolicy set on Certificates that do not specify one
+	DeletionPolicy certificatev1beta1.CertificateDeletionPolicy
+
+	// Tags added to every Certificate. Tags of the Certificate take precedence.
+	Tags map[string]string
+
+	// Reader used to look up CertificateClasses. Classes are ignored when nil.
+	Reader client.Reader
+}
+
+//+kubebuilder:webhook:path=/mutate-acm-manager-io-v1beta1-certificate,mutating=true,failurePolicy=fail,sideEffects=None,groups=acm-manager.io,resources=certificates,verbs=create;update,versions=v1beta1,name=mcertificate.acm-manager.io,admissionReviewVersions=v1
+
+// SetupWithManager registers the defaulting webhook with the Manager.
+func (d *CertificateDefaulter) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &certificatev1beta1.Certificate{}).
+		WithDefaulter(d).
+		Complete()
+}
+
+// Default implements admission.Defaulter.
+func (d *CertificateDefaulter) Default(ctx context.Context, cert *certificatev1beta1.Certificate) error {
+	cert.Spec.CommonName = normalizeDomainName(cert.Spec.CommonName)
+
+	sans := make([]string, 0, len(cert.Spec.SubjectAlternativeNames)+1)
+	for _, san := range cert.Spec.SubjectAlternativeNames {
+		sans = append(sans, normalizeDomainName(san))
+	}
+	if cert.Spec.CommonName != "" {
+		sans = append(sans, cert.Spec.CommonName)
+	}
+	slices.Sort(sans)
+	cert.Spec.SubjectAlternativeNames = slices.Compact(sans)
+
+	// the class is recorded in the spec and the controller defaults only fill
+	// the fields the class does not set, the controller applies the class
+	effective := cert.Spec.DeepCopy()
+	if d.Reader != nil {
+		class, err := controllers.GetCertificateClass(ctx, d.Reader, cert.Spec.CertificateClassName)
+		var classErr *controllers.CertificateClassNotFoundError
+		if err != nil && !errors.As(err, &classErr) {
+			return err
+		}
+		if class != nil {
+			cert.Spec.CertificateClassName = class.Name
+			class.Spec.ApplyTo(effective)
+		}
+	}
+
+	if effective.Validation == nil || effective.Validation.Method == "" {
+		if cert.Spec.Validation == nil {
+			cert.Spec.Validation = &certificatev1beta1.CertificateValidation{}
+		}
+		cert.Spec.Validation.Method = certificatev1beta1.CertificateValidationMethodDNS
+	}
+	if effective.KeyAlgorithm() == "" && d.KeyAlgorithm != "" {
+		if cert.Spec.Options == nil {
+			cert.Spec.Options = &certificatev1beta1.CertificateOptions{}
+		}
+		cert.Spec.Options.KeyAlgorithm = d.KeyAlgorithm
+	}
+	if effective.DeletionPolicy == "" {
+		cert.Spec.DeletionPolicy = d.DeletionPolicy
+	}
+	for k, v := range d.Tags {
+		if _, ok := cert.Spec.Tags[k]; ok {
+			continue
+		}
+		if cert.Spec.Tags == nil {
+			cert.Spec.Tags = map[string]string{}
+		}
+		cert.Spec.Tags[k] = v
+	}
+
+	return nil
+}
+
+// normalizeDomainName lowercases a domain name, converts it to punycode and
+// removes its trailing dot. Names that can not be converted are only
+// lowercased and left for the validation to report.
+func normalizeDomainName(name string) string {
+	name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+
+	// the wildcard label is not a valid IDNA label
+	wildcard := strings.HasPrefix(name, "*.")
+	ascii, err := idna.Lookup.ToASCII(strings.TrimPrefix(name, "*."))
+	if err != nil {
+		return name
+	}
+	if wildcard {
+		return "*." + ascii
+	}
+	return ascii
+}
+
+//+kubebuilder:webhook:path=/validate-acm-manager-io-v1beta1-certificate,mutating=false,failurePolicy=fail,sideEffects=None,groups=acm-manager.io,resources=certificates,verbs=create;update,versions=v1beta1,name=vcertificate.acm-manager.io,admissionReviewVersions=v1
+
+// SetupWithManager registers the validating webhook with the Manager.
+func (v *CertificateValidator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &certificatev1beta1.Certificate{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate implements admission.Validator.
+func (v *CertificateValidator) ValidateCreate(ctx context.Context, cert *certificatev1beta1.Certificate) (admission.Warnings, error) {
+	if err := v.validate(cert); err != nil {
+		return nil, err
+	}
+	return nil, v.checkPolicy(ctx, cert)
+}
+
+// ValidateUpdate implements admission.Validator. A warning is returned when the
+// change forces a new ACM certificate request.
+func (v *CertificateValidator) ValidateUpdate(ctx context.Context, oldCert, cert *certificatev1beta1.Certificate) (admission.Warnings, error) {
+	if err := v.validate(cert); err != nil {
+		return nil, err
+	}
+	if err := v.checkPolicy(ctx, cert); err != nil {
+		return nil, err
+	}
+
+	var warnings admission.Warnings
+	if fields := controllers.CertificateReplacedFields(&oldCert.Spec, &cert.Spec); len(fields) > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"changing %s of Certificate %s/%s requests a new ACM certificate, the current one is deleted once the new one is issued",
+			strings.Join(fields, ", "), cert.Namespace, cert.Name))
+	}
+
+	return warnings, nil
+}
+
+// ValidateDelete implements admission.Validator.
+func (v *CertificateValidator) ValidateDelete(ctx context.Context, cert *certificatev1beta1.Certificate) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *CertificateValidator) validate(cert *certificatev1beta1.Certificate) error {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+	cnPath := specPath.Child("commonName")
+	sansPath := specPath.Child("subjectAlternativeNames")
+
+	cn := cert.Spec.CommonName
+	switch {
+	case cn == "":
+		errs = append(errs, field.Required(cnPath, "common name is required"))
+	case len(cn) > maxCommonNameLength:
+		errs = append(errs, field.TooLong(cnPath, cn, maxCommonNameLength))
+	}
+	if err := validateWildcard(cn); err != "" {
+		errs = append(errs, field.Invalid(cnPath, cn, err))
+	}
+
+	seen := map[string]bool{}
+	for i, san := range cert.Spec.SubjectAlternativeNames {
+		key := strings.ToLower(san)
+		if seen[key] {
+			errs = append(errs, field.Duplicate(sansPath.Index(i), san))
+			continue
+		}
+		seen[key] = true
+
+		if err := validateWildcard(san); err != "" {
+			errs = append(errs, field.Invalid(sansPath.Index(i), san, err))
+		}
+	}
+
+	if cn != "" && !seen[strings.ToLower(cn)] {
+		errs = append(errs, field.Invalid(sansPath, cert.Spec.SubjectAlternativeNames,
+			fmt.Sprintf("must contain the common name %q", cn)))
+	}
+
+	for k := range cert.Spec.Tags {
+		if strings.HasPrefix(k, controllers.TagReservedPrefix) {
+			errs = append(errs, field.Invalid(specPath.Child("tags").Key(k), k,
+				fmt.Sprintf("tags starting with %s are reserved", controllers.TagReservedPrefix)))
+		}
+	}
+
+	max := v.MaxSubjectAlternativeNames
+	if max <= 0 {
+		max = DefaultMaxSubjectAlternativeNames
+	}
+	if len(seen) > max {
+		errs = append(errs, field.TooMany(sansPath, len(seen), max))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(certificatev1beta1.SchemeGroupVersion.WithKind("Certificate").GroupKind(), cert.Name, errs)
+}
+
+// checkPolicy returns a Forbidden error when the DomainPolicies of the
+// namespace deny the Certificate.
+func (v *CertificateValidator) checkPolicy(ctx context.Context, cert *certificatev1beta1.Certificate) error {
+	if v.Policy == nil {
+		return nil
+	}
+
+	domains := append([]string{cert.Spec.CommonName}, cert.Spec.SubjectAlternativeNames...)
+	return policyError(v.Policy.Check(ctx, cert.Namespace, cert.Name, domains),
+		certificatev1beta1.SchemeGroupVersion.WithResource("certificates").GroupResource(), cert.Name)
+}
+
+// policyError converts a policy denial to a Forbidden API error.
+func policyError(err error, resource schema.GroupResource, name string) error {
+	var deniedErr *policy.DeniedError
+	if errors.As(err, &deniedErr) {
+		return apierrors.NewForbidden(resource, name, err)
+	}
+	return err
+}
+
+// validateWildcard returns why a domain name is a malformed wildcard, or an
+// empty string. ACM only supports a single wildcard as the whole leftmost label
+// of a name that has at least two other labels.
+func validateWildcard(name string) string {
+	if !strings.Contains(name, "*") {
+		return ""
+	}
+	if strings.Count(name, "*") > 1 {
+		return "only one wildcard is allowed"
+	}
+	if !strings.HasPrefix(name, "*.") {
+		return "wildcard must be the whole leftmost label"
+	}
+	if strings.Count(strings.TrimSuffix(name[2:], "."), ".") < 1 {
+		return "wildcard must be followed by at least two labels"
+	}
+	return ""
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+)
+
+func newCertificate(cn string, sans ...string) *certificatev1beta1.Certificate {
+	return &certificatev1beta1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec: certificatev1beta1.CertificateSpec{
+			CommonName:              cn,
+			SubjectAlternativeNames: sans,
+		},
+	}
+}
+
+var _ = Describe("Certificate validating webhook", func() {
+	validator := &CertificateValidator{MaxSubjectAlternativeNames: 3}
+
+	It("Should accept a valid certificate", func() {
+		cert := newCertificate("test.example.com", "test.example.com", "*.test.example.com")
+
+		warnings, err := validator.ValidateCreate(context.Background(), cert)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	table.DescribeTable("Should reject invalid certificates",
+		func(cert *certificatev1beta1.Certificate, message string) {
+			_, err := validator.ValidateCreate(context.Background(), cert)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(message))
+		},
+		table.Entry("missing common name", newCertificate("", "test.example.com"), "spec.commonName: Required value"),
+		table.Entry("common name too long",
+			newCertificate(strings.Repeat("a", 60)+".example.com", strings.Repeat("a", 60)+".example.com"),
+			"spec.commonName: Too long"),
+		table.Entry("duplicate SANs",
+			newCertificate("test.example.com", "test.example.com", "Test.example.com"),
+			"spec.subjectAlternativeNames[1]: Duplicate value"),
+		table.Entry("too many SANs",
+			newCertificate("a.example.com", "a.example.com", "b.example.com", "c.example.com", "d.example.com"),
+			"spec.subjectAlternativeNames: Too many: 4: must have at most 3 items"),
+		table.Entry("common name missing from SANs",
+			newCertificate("test.example.com", "other.example.com"),
+			"must contain the common name"),
+		table.Entry("wildcard not in leftmost label",
+			newCertificate("test.example.com", "test.example.com", "test.*.example.com"),
+			"wildcard must be the whole leftmost label"),
+		table.Entry("partial wildcard label",
+			newCertificate("test.example.com", "test.example.com", "te*.example.com"),
+			"wildcard must be the whole leftmost label"),
+		table.Entry("multiple wildcards",
+			newCertificate("test.example.com", "test.example.com", "*.*.example.com"),
+			"only one wildcard is allowed"),
+		table.Entry("reserved tag",
+			func() *certificatev1beta1.Certificate {
+				cert := newCertificate("test.example.com", "test.example.com")
+				cert.Spec.Tags = map[string]string{"acm-manager/owner": "other"}
+				return cert
+			}(),
+			"tags starting with acm-manager/ are reserved"),
+		table.Entry("wildcard on a top level domain",
+			newCertificate("test.example.com", "test.example.com", "*.com"),
+			"wildcard must be followed by at least two labels"),
+	)
+
+	It("Should use the ACM default quota when no maximum is set", func() {
+		sans := []string{}
+		for i := 0; i <= DefaultMaxSubjectAlternativeNames; i++ {
+			sans = append(sans, fmt.Sprintf("test%d.example.com", i))
+		}
+
+		_, err := (&CertificateValidator{}).ValidateCreate(context.Background(), newCertificate(sans[0], sans...))
+		Expect(err).To(MatchError(ContainSubstring("Too many")))
+	})
+
+	It("Should warn when an update requests a new ACM certificate", func() {
+		oldCert := newCertificate("test.example.com", "test.example.com", "a.example.com")
+
+		By("ignoring the order of SANs")
+		warnings, err := validator.ValidateUpdate(context.Background(), oldCert,
+			newCertificate("test.example.com", "a.example.com", "test.example.com"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+
+		By("warning when the SANs change")
+		warnings, err = validator.ValidateUpdate(context.Background(), oldCert,
+			newCertificate("test.example.com", "test.example.com", "b.example.com"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("requests a new ACM certificate")))
+
+		By("warning when another field ACM can not change is updated")
+		newCert := newCertificate("test.example.com", "test.example.com", "a.example.com")
+		newCert.Spec.Options = &certificatev1beta1.CertificateOptions{KeyAlgorithm: certificatev1beta1.CertificateKeyAlgorithmECPrime256v1}
+		newCert.Spec.Region = "us-east-1"
+		warnings, err = validator.ValidateUpdate(context.Background(), oldCert, newCert)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("changing region, options.keyAlgorithm of")))
+	})
+})
+
+var _ = Describe("Certificate defaulting webhook", func() {
+	defaulter := &CertificateDefaulter{
+		KeyAlgorithm:   certificatev1beta1.CertificateKeyAlgorithmRSA2048,
+		DeletionPolicy: certificatev1beta1.CertificateDeletionPolicyDelete,
+		Tags:           map[string]string{"team": "platform", "env": "prod"},
+	}
+
+	It("Should normalize the domain names", func() {
+		cert := newCertificate("Test.Example.com.", "b.example.com", "*.Bücher.example.com", "B.EXAMPLE.COM")
+
+		Expect(defaulter.Default(context.Background(), cert)).To(Succeed())
+		Expect(cert.Spec.CommonName).To(Equal("test.example.com"))
+		Expect(cert.Spec.SubjectAlternativeNames).To(Equal([]string{
+			"*.xn--bcher-kva.example.com",
+			"b.example.com",
+			"test.example.com",
+		}))
+	})
+
+	It("Should fill in the controller defaults", func() {
+		cert := newCertificate("test.example.com")
+		cert.Spec.DeletionPolicy = certificatev1beta1.CertificateDeletionPolicyRetain
+		cert.Spec.Tags = map[string]string{"team": "web"}
+
+		Expect(defaulter.Default(context.Background(), cert)).To(Succeed())
+		Expect(cert.Spec.KeyAlgorithm()).To(Equal(certificatev1beta1.CertificateKeyAlgorithmRSA2048))
+		Expect(cert.Spec.ValidationMethod()).To(Equal(certificatev1beta1.CertificateValidationMethodDNS))
+		Expect(cert.Spec.Validation).NotTo(BeNil())
+		Expect(cert.Spec.DeletionPolicy).To(Equal(certificatev1beta1.CertificateDeletionPolicyRetain))
+		Expect(cert.Spec.Tags).To(Equal(map[string]string{"team": "web", "env": "prod"}))
+	})
+
+	It("Should produce certificates accepted by the validating webhook", func() {
+		cert := newCertificate("Test.Example.com", "test.example.com", "test.example.com")
+
+		Expect(defaulter.Default(context.Background(), cert)).To(Succeed())
+		_, err := (&CertificateValidator{}).ValidateCreate(context.Background(), cert)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should record the default class and leave its options to the controller", func() {
+		scheme := runtime.NewScheme()
+		Expect(certificatev1beta1.AddToScheme(scheme)).To(Succeed())
+		class := &certificatev1beta1.CertificateClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "ecdsa",
+				Annotations: map[string]string{certificatev1beta1.DefaultCertificateClassAnnotation: "true"},
+			},
+			Spec: certificatev1beta1.CertificateClassSpec{
+				Options: &certificatev1beta1.CertificateOptions{KeyAlgorithm: certificatev1beta1.CertificateKeyAlgorithmECPrime256v1},
+			},
+		}
+		d := *defaulter
+		d.Reader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(class).Build()
+
+		cert := newCertificate("test.example.com")
+		Expect(d.Default(context.Background(), cert)).To(Succeed())
+		Expect(cert.Spec.CertificateClassName).To(Equal("ecdsa"))
+		Expect(cert.Spec.KeyAlgorithm()).To(BeEmpty())
+		Expect(cert.Spec.DeletionPolicy).To(Equal(certificatev1beta1.CertificateDeletionPolicyDelete))
+
+		By("keeping a class that does not exist for the controller to report")
+		cert = newCertificate("test.example.com")
+		cert.Spec.CertificateClassName = "missing"
+		Expect(d.Default(context.Background(), cert)).To(Succeed())
+		Expect(cert.Spec.CertificateClassName).To(Equal("missing"))
+		Expect(cert.Spec.KeyAlgorithm()).To(Equal(certificatev1beta1.CertificateKeyAlgorithmRSA2048))
+	})
+})
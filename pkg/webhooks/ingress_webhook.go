@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"vdesjardins/acm-manager/pkg/controllers"
+	"vdesjardins/acm-manager/pkg/policy"
+)
+
+// IngressValidator rejects the Ingresses whose generated Certificates would be
+// denied by the DomainPolicies of their namespace.
+type IngressValidator struct {
+	Policy *policy.Checker
+	// Reader resolves the IngressClasses of the Ingresses
+	Reader client.Reader
+}
+
+// Ingresses are not owned by this controller, the webhook fails open and the
+// Certificate controller still enforces the policies.
+//+kubebuilder:webhook:path=/validate-networking-k8s-io-v1-ingress,mutating=false,failurePolicy=ignore,sideEffects=None,groups=networking.k8s.io,resources=ingresses,verbs=create;update,versions=v1,name=vingress.acm-manager.io,admissionReviewVersions=v1
+
+// SetupWithManager registers the validating webhook with the Manager.
+func (v *IngressValidator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &networkingv1.Ingress{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate implements admission.Validator.
+func (v *IngressValidator) ValidateCreate(ctx context.Context, ingress *networkingv1.Ingress) (admission.Warnings, error) {
+	return nil, v.validate(ctx, ingress)
+}
+
+// ValidateUpdate implements admission.Validator.
+func (v *IngressValidator) ValidateUpdate(ctx context.Context, oldIngress, ingress *networkingv1.Ingress) (admission.Warnings, error) {
+	return nil, v.validate(ctx, ingress)
+}
+
+// ValidateDelete implements admission.Validator.
+func (v *IngressValidator) ValidateDelete(ctx context.Context, ingress *networkingv1.Ingress) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *IngressValidator) validate(ctx context.Context, ingress *networkingv1.Ingress) error {
+	if v.Policy == nil {
+		return nil
+	}
+	shouldCreate, err := controllers.IsIngressShouldCreateCert(ctx, v.Reader, ingress)
+	if err != nil || !shouldCreate {
+		return err
+	}
+	if ingress.GetAnnotations()[controllers.IngressCertificateRefKey] != "" {
+		// no Certificate is generated for the referenced one
+		return nil
+	}
+	hosts := controllers.IngressHosts(ingress)
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	resource := networkingv1.SchemeGroupVersion.WithResource("ingresses").GroupResource()
+
+	if ingress.GetAnnotations()[controllers.IngressGroupNameKey] != "" && controllers.IngressGroupNamespace != "" {
+		// the group Certificates are not in the namespace of the Ingress, only
+		// its domains are checked, like the IngressGroupReconciler does
+		denied, err := v.Policy.DeniedDomains(ctx, ingress.Namespace, hosts)
+		if err != nil || len(denied) == 0 {
+			return err
+		}
+		return policyError(&policy.DeniedError{
+			Namespace: ingress.Namespace,
+			Message:   fmt.Sprintf("domains %s are not allowed", strings.Join(denied, ", ")),
+		}, resource, ingress.Name)
+	}
+
+	name, err := controllers.IngressCertificateName(ingress)
+	if err != nil {
+		// reported on the Ingress by the controller
+		name = ingress.Name
+	}
+	partitions, err := controllers.IngressCertificatePartitions(ctx, v.Reader, ingress, name, hosts)
+	if err != nil {
+		return err
+	}
+	requests := make([]policy.Request, 0, len(partitions))
+	for _, partition := range partitions {
+		requests = append(requests, policy.Request{Name: partition.Name, Domains: partition.Hosts})
+	}
+	// the partitions that do not exist yet count against the maximum number
+	// of Certificates of each other
+	if err := v.Policy.CheckAll(ctx, ingress.Namespace, requests); err != nil {
+		return policyError(err, resource, ingress.Name)
+	}
+	return nil
+}
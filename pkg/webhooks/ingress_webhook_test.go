@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The acm-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	certificatev1beta1 "vdesjardins/acm-manager/pkg/apis/acmmanager/v1beta1"
+	"vdesjardins/acm-manager/pkg/controllers"
+	"vdesjardins/acm-manager/pkg/policy"
+)
+
+func newDomainPolicyChecker(objs ...client.Object) *policy.Checker {
+	scheme := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	Expect(certificatev1beta1.AddToScheme(scheme)).To(Succeed())
+
+	objs = append(objs,
+		&core.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"team": "web"}}},
+		&certificatev1beta1.DomainPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+			Spec: certificatev1beta1.DomainPolicySpec{
+				NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+				AllowedDomains:    []string{"*.web.example.com"},
+				MaxCertificates:   ptr.To[int32](1),
+			},
+		},
+	)
+	return &policy.Checker{Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()}
+}
+
+func newPolicyIngress(hosts ...string) *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Namespace:   "default",
+			UID:         "test-uid",
+			Annotations: map[string]string{controllers.ACMManagerCreateCertificateKey: "true"},
+		},
+		Spec: networkingv1.IngressSpec{
+			TLS: []networkingv1.IngressTLS{{Hosts: hosts}},
+		},
+	}
+}
+
+var _ = Describe("Domain policy webhooks", func() {
+	It("Should forbid a Certificate outside of the allowed domains", func() {
+		validator := &CertificateValidator{Policy: newDomainPolicyChecker()}
+
+		_, err := validator.ValidateCreate(context.Background(), newCertificate("app.web.example.com", "app.web.example.com"))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = validator.ValidateCreate(context.Background(), newCertificate("app.web.example.com", "app.web.example.com", "api.example.com"))
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("api.example.com"))
+	})
+
+	It("Should forbid an Ingress creating a Certificate outside of the allowed domains", func() {
+		checker := newDomainPolicyChecker()
+		validator := &IngressValidator{Policy: checker, Reader: checker.Reader}
+		ingress := newPolicyIngress("api.example.com")
+
+		_, err := validator.ValidateCreate(context.Background(), ingress)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+
+		By("ignoring the Ingresses that do not create a Certificate")
+		ingress.Annotations[controllers.ACMManagerCreateCertificateKey] = "false"
+		_, err = validator.ValidateCreate(context.Background(), ingress)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should check each Certificate generated for the hosts of an Ingress", func() {
+		defer func(max int) { controllers.IngressMaxCertificateHosts = max }(controllers.IngressMaxCertificateHosts)
+		controllers.IngressMaxCertificateHosts = 1
+		checker := newDomainPolicyChecker(&certificatev1beta1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "test",
+				Namespace:       "default",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Ingress", Name: "test", UID: "test-uid"}},
+			},
+			Spec: certificatev1beta1.CertificateSpec{
+				CommonName:              "a.web.example.com",
+				SubjectAlternativeNames: []string{"a.web.example.com"},
+			},
+		})
+		validator := &IngressValidator{Policy: checker, Reader: checker.Reader}
+
+		_, err := validator.ValidateUpdate(context.Background(), nil, newPolicyIngress("a.web.example.com"))
+		Expect(err).NotTo(HaveOccurred())
+
+		By("forbidding the second Certificate above the maximum")
+		_, err = validator.ValidateUpdate(context.Background(), nil, newPolicyIngress("a.web.example.com", "b.web.example.com"))
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("at most 1 certificates"))
+	})
+
+	It("Should count the Certificates of a new Ingress against each other", func() {
+		defer func(max int) { controllers.IngressMaxCertificateHosts = max }(controllers.IngressMaxCertificateHosts)
+		controllers.IngressMaxCertificateHosts = 1
+		checker := newDomainPolicyChecker()
+		validator := &IngressValidator{Policy: checker, Reader: checker.Reader}
+
+		_, err := validator.ValidateCreate(context.Background(), newPolicyIngress("a.web.example.com", "b.web.example.com"))
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("at most 1 certificates"))
+	})
+
+	It("Should only check the domains of the Ingresses of a group", func() {
+		defer func(namespace string) { controllers.IngressGroupNamespace = namespace }(controllers.IngressGroupNamespace)
+		controllers.IngressGroupNamespace = "acm-manager"
+		checker := newDomainPolicyChecker(&certificatev1beta1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+		})
+		validator := &IngressValidator{Policy: checker, Reader: checker.Reader}
+		ingress := newPolicyIngress("a.web.example.com")
+		ingress.Annotations[controllers.IngressGroupNameKey] = "shared"
+
+		_, err := validator.ValidateCreate(context.Background(), ingress)
+		Expect(err).NotTo(HaveOccurred())
+
+		ingress.Spec.TLS[0].Hosts = append(ingress.Spec.TLS[0].Hosts, "api.example.com")
+		_, err = validator.ValidateCreate(context.Background(), ingress)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("api.example.com"))
+	})
+})